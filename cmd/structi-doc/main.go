@@ -0,0 +1,186 @@
+// Command structi-doc generates a reference page listing every setting
+// of a config struct, e.g.:
+//
+//	structi-doc -dir ./config -type Config -format markdown -o CONFIG.md
+//
+// It reads the struct definition from the source code of the package,
+// so it can be used from `go generate` without building the package,
+// and lists the settings following the same rules as docgen.Collect().
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/vingarcia/structi/docgen"
+	"github.com/vingarcia/structi/tags"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "structi-doc:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("structi-doc", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "directory of the package containing the config struct")
+	typeName := flags.String("type", "", "name of the config struct type (required)")
+	format := flags.String("format", "markdown", "output format, either markdown or html")
+	output := flags.String("o", "", "output file, defaults to stdout")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *typeName == "" {
+		return fmt.Errorf("missing required -type argument")
+	}
+
+	settings, err := collectFromSource(*dir, *typeName)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch *format {
+	case "markdown":
+		err = docgen.Markdown(&buf, settings)
+	case "html":
+		err = docgen.HTML(&buf, settings)
+	default:
+		return fmt.Errorf("unknown format '%s', expected markdown or html", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(buf.Bytes())
+		return err
+	}
+
+	return os.WriteFile(*output, buf.Bytes(), 0644)
+}
+
+// collectFromSource works like docgen.Collect() but reads
+// the struct definitions from the source files on `dir`.
+func collectFromSource(dir string, typeName string) ([]docgen.Setting, error) {
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("struct type %s not found on directory %s", typeName, dir)
+	}
+
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a struct, but %v", typeName, obj.Type().Underlying())
+	}
+
+	return docgen.CollectFrom(sourceStruct{pkg: pkg, st: st})
+}
+
+// loadPackage parses and type checks the non-test Go files of `dir`.
+func loadPackage(dir string) (*types.Package, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+	for _, filename := range filenames {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filename, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found on directory %s", dir)
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+	}
+	return conf.Check(files[0].Name.Name, fset, files, nil)
+}
+
+// sourceStruct implements the docgen.StructType interface
+// for struct types read from the source code, so that the
+// same rules of docgen.Collect() are used for both of them.
+type sourceStruct struct {
+	pkg *types.Package
+	st  *types.Struct
+}
+
+func (s sourceStruct) Fields() ([]docgen.StructField, error) {
+	fields := []docgen.StructField{}
+	for i := 0; i < s.st.NumFields(); i++ {
+		field := s.st.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		fieldTags, err := tags.ParseTags(reflect.StructTag(s.st.Tag(i)))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing tags of field %s: %w", field.Name(), err)
+		}
+
+		elemType := field.Type()
+		for {
+			ptr, ok := elemType.(*types.Pointer)
+			if !ok {
+				break
+			}
+			elemType = ptr.Elem()
+		}
+
+		var st docgen.StructType
+		if nested, ok := elemType.Underlying().(*types.Struct); ok && !isTime(elemType) {
+			st = sourceStruct{pkg: s.pkg, st: nested}
+		}
+
+		fields = append(fields, docgen.StructField{
+			Name:     field.Name(),
+			Embedded: field.Embedded(),
+			TypeName: types.TypeString(field.Type(), types.RelativeTo(s.pkg)),
+			Tags:     fieldTags,
+			Struct:   st,
+		})
+	}
+
+	return fields, nil
+}
+
+func isTime(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time"
+}
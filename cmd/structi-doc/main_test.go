@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vingarcia/structi/docgen"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+const configSource = `package config
+
+import (
+	"image"
+	"time"
+)
+
+type Base struct {
+	Debug bool ` + "`env:\"DEBUG\" flag:\"debug\"`" + `
+}
+
+type DBConfig struct {
+	Host    string        ` + "`env:\"DB_HOST\" default:\"localhost\" description:\"The database host\"`" + `
+	Timeout time.Duration ` + "`env:\"DB_TIMEOUT\" default:\"5s\"`" + `
+}
+
+type Config struct {
+	Base
+
+	Name     string ` + "`env:\"NAME\" validate:\"required\"`" + `
+	DB       *DBConfig
+	Metrics  struct {
+		Port int ` + "`env:\"METRICS_PORT\"`" + `
+	}
+	Parent   *Config
+	Origin   image.Point
+	internal string
+}
+
+func newDBConfig() any {
+	// Types declared inside functions should not
+	// be confused with the ones of the package:
+	type DBConfig struct {
+		Other int ` + "`env:\"OTHER\"`" + `
+	}
+	return DBConfig{}
+}
+`
+
+func TestCollectFromSource(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(configSource), 0644)
+	tt.AssertNoErr(t, err)
+
+	settings, err := collectFromSource(dir, "Config")
+	tt.AssertNoErr(t, err)
+	tt.AssertEqual(t, settings, []docgen.Setting{
+		{Path: "Debug", EnvVar: "DEBUG", Flag: "debug", Type: "bool"},
+		{Path: "Name", EnvVar: "NAME", Type: "string", Required: true},
+		{Path: "DB.Host", EnvVar: "DB_HOST", Type: "string", Default: "localhost", Description: "The database host"},
+		{Path: "DB.Timeout", EnvVar: "DB_TIMEOUT", Type: "time.Duration", Default: "5s"},
+		{Path: "Metrics.Port", EnvVar: "METRICS_PORT", Type: "int"},
+		{Path: "Parent", Type: "*Config"},
+		{Path: "Origin.X", Type: "int"},
+		{Path: "Origin.Y", Type: "int"},
+	})
+
+	t.Run("should report error if the type is not found", func(t *testing.T) {
+		_, err := collectFromSource(dir, "NotFound")
+		tt.AssertErrContains(t, err, "NotFound", "not found")
+	})
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(configSource), 0644)
+	tt.AssertNoErr(t, err)
+
+	t.Run("should write the output to stdout", func(t *testing.T) {
+		var stdout bytes.Buffer
+		err := run([]string{"-dir", dir, "-type", "DBConfig"}, &stdout)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, stdout.String(), ""+
+			"| Path | Env Var | Flag | Type | Default | Required | Description |\n"+
+			"| ---- | ------- | ---- | ---- | ------- | -------- | ----------- |\n"+
+			"| `Host` | `DB_HOST` |  | `string` | `localhost` | no | The database host |\n"+
+			"| `Timeout` | `DB_TIMEOUT` |  | `time.Duration` | `5s` | no |  |\n",
+		)
+	})
+
+	t.Run("should write the output to a file", func(t *testing.T) {
+		output := filepath.Join(dir, "CONFIG.html")
+		err := run([]string{"-dir", dir, "-type", "DBConfig", "-format", "html", "-o", output}, &bytes.Buffer{})
+		tt.AssertNoErr(t, err)
+
+		b, err := os.ReadFile(output)
+		tt.AssertNoErr(t, err)
+		tt.AssertTrue(t, strings.Contains(string(b), "<code>DB_HOST</code>"), "unexpected output: %s", string(b))
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		err := run([]string{"-dir", dir}, &bytes.Buffer{})
+		tt.AssertErrContains(t, err, "missing", "-type")
+
+		err = run([]string{"-dir", dir, "-type", "Config", "-format", "pdf"}, &bytes.Buffer{})
+		tt.AssertErrContains(t, err, "unknown format", "pdf")
+	})
+}
@@ -0,0 +1,284 @@
+// Command structi-gen generates reflection-free iterators for struct
+// types, which are used automatically by structi.ForEach(), e.g.:
+//
+//	//go:generate go run github.com/vingarcia/structi/cmd/structi-gen -type Config
+//
+// It reads the struct definitions from the package on the current
+// directory (or on -dir) and writes the iterators to a file named
+// after the package, e.g. `config_structi.go`.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "structi-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("structi-gen", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "directory of the package containing the struct types")
+	typeNames := flags.String("type", "", "comma separated list of struct type names (required)")
+	output := flags.String("o", "", "output file, defaults to <package>_structi.go")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *typeNames == "" {
+		return fmt.Errorf("missing required -type argument")
+	}
+
+	pkg, err := loadPackage(*dir)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(pkg, strings.Split(*typeNames, ","))
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		*output = filepath.Join(*dir, pkg.Name()+"_structi.go")
+	}
+
+	return os.WriteFile(*output, src, 0644)
+}
+
+// loadPackage parses and type checks the non-test Go files of `dir`.
+func loadPackage(dir string) (*types.Package, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+	for _, filename := range filenames {
+		if strings.HasSuffix(filename, "_test.go") || strings.HasSuffix(filename, "_structi.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filename, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found on directory %s", dir)
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+
+		// Type errors on unrelated parts of the package should not
+		// prevent the generation, the fields with invalid types just
+		// won't have the reflection-free Set implementation:
+		Error: func(err error) {},
+	}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, nil)
+	return pkg, nil
+}
+
+type templateField struct {
+	Idx  int
+	Name string
+
+	// Type is left empty if the field should
+	// always be set using reflection.
+	Type string
+}
+
+type templateType struct {
+	Name   string
+	Fields []templateField
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by structi-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{printf "%q" .}}
+{{- end}}
+
+	"github.com/vingarcia/structi"
+)
+
+func init() {
+{{- range .Types}}
+	structi.RegisterGeneratedIterator((*{{.Name}})(nil), func(structPtr any, yield func(idx int, valuePtr any, trySet func(value any) bool) error) error {
+		s := structPtr.(*{{.Name}})
+{{- range .Fields}}
+{{- if .Type}}
+		if err := yield({{.Idx}}, &s.{{.Name}}, func(value any) bool {
+			v, ok := value.({{.Type}})
+			if ok {
+				s.{{.Name}} = v
+			}
+			return ok
+		}); err != nil {
+			return err
+		}
+{{- else}}
+		if err := yield({{.Idx}}, &s.{{.Name}}, nil); err != nil {
+			return err
+		}
+{{- end}}
+{{- end}}
+		return nil
+	})
+{{- end}}
+}
+`))
+
+// generate builds the source code of the iterators for the given types.
+func generate(pkg *types.Package, typeNames []string) ([]byte, error) {
+	imports := map[string]bool{}
+	qualifier := func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		imports[other.Path()] = true
+		return other.Name()
+	}
+
+	templateTypes := []templateType{}
+	for _, typeName := range typeNames {
+		typeName = strings.TrimSpace(typeName)
+
+		obj := pkg.Scope().Lookup(typeName)
+		if obj == nil {
+			return nil, fmt.Errorf("type %s not found on package %s", typeName, pkg.Name())
+		}
+
+		named, ok := obj.Type().(*types.Named)
+		if !ok {
+			return nil, fmt.Errorf("expected %s to be a named type", typeName)
+		}
+
+		if named.TypeParams().Len() > 0 {
+			return nil, fmt.Errorf("generic types are not supported, but %s has type parameters", typeName)
+		}
+
+		st, ok := named.Underlying().(*types.Struct)
+		if !ok {
+			return nil, fmt.Errorf("expected %s to be a struct type, but got: %s", typeName, named.Underlying())
+		}
+
+		t := templateType{Name: typeName}
+		for i := 0; i < st.NumFields(); i++ {
+			field := st.Field(i)
+			if !field.Exported() {
+				continue
+			}
+
+			fieldType := ""
+			if hasFastSet(field.Type()) && isValid(pkg, field.Type()) {
+				fieldType = types.TypeString(field.Type(), qualifier)
+			}
+
+			t.Fields = append(t.Fields, templateField{
+				Idx:  i,
+				Name: field.Name(),
+				Type: fieldType,
+			})
+		}
+
+		templateTypes = append(templateTypes, t)
+	}
+
+	importList := []string{}
+	for path := range imports {
+		importList = append(importList, path)
+	}
+	sort.Strings(importList)
+
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, map[string]any{
+		"Package": pkg.Name(),
+		"Imports": importList,
+		"Types":   templateTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error formatting generated code: %w\n%s", err, buf.String())
+	}
+
+	return src, nil
+}
+
+// hasFastSet checks if setting a value of the exact same type of the field
+// without reflection has the same semantics of using reflection, which is
+// not the case for pointers and slices since the reflection-based Set()
+// copies them instead of sharing the same memory.
+func hasFastSet(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Basic, *types.Struct, *types.Array, *types.Map:
+		return true
+	}
+	return false
+}
+
+// isValid checks if the type and all its component types were
+// successfully resolved by the type checker and can be referenced
+// from the generated code.
+func isValid(pkg *types.Package, t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Basic:
+		return t.Kind() != types.Invalid
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() != nil && obj.Pkg() != pkg && !obj.Exported() {
+			return false
+		}
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			if !isValid(pkg, t.TypeArgs().At(i)) {
+				return false
+			}
+		}
+	case *types.Pointer:
+		return isValid(pkg, t.Elem())
+	case *types.Slice:
+		return isValid(pkg, t.Elem())
+	case *types.Array:
+		return isValid(pkg, t.Elem())
+	case *types.Map:
+		return isValid(pkg, t.Key()) && isValid(pkg, t.Elem())
+	case *types.Chan:
+		return isValid(pkg, t.Elem())
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if !isValid(pkg, t.Field(i).Type()) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+const configSource = `package config
+
+import (
+	"time"
+
+	"example.com/does/not/exist"
+)
+
+type Config struct {
+	Host     string
+	Timeout  time.Duration
+	Tags     []string
+	Unknown  exist.Type
+	internal int
+}
+
+type NotAStruct int
+`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(configSource), 0644)
+	tt.AssertNoErr(t, err)
+
+	pkg, err := loadPackage(dir)
+	tt.AssertNoErr(t, err)
+
+	t.Run("should generate iterators for the struct types", func(t *testing.T) {
+		src, err := generate(pkg, []string{"Config"})
+		tt.AssertNoErr(t, err)
+
+		_, err = parser.ParseFile(token.NewFileSet(), "config_structi.go", src, 0)
+		tt.AssertNoErr(t, err)
+
+		code := string(src)
+		for _, expected := range []string{
+			"// Code generated by structi-gen. DO NOT EDIT.",
+			"package config",
+			`"time"`,
+			"structi.RegisterGeneratedIterator((*Config)(nil)",
+			"yield(0, &s.Host, func(value any) bool {",
+			"v, ok := value.(string)",
+			"yield(1, &s.Timeout, func(value any) bool {",
+			"v, ok := value.(time.Duration)",
+			// Slices are always set with reflection since Set() copies them:
+			"yield(2, &s.Tags, nil)",
+			// Types that could not be resolved are also set with reflection:
+			"yield(3, &s.Unknown, nil)",
+		} {
+			tt.AssertTrue(t, strings.Contains(code, expected), "missing %q on generated code:\n%s", expected, code)
+		}
+		tt.AssertTrue(t, !strings.Contains(code, "internal"), "unexported fields should be ignored:\n%s", code)
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		_, err := generate(pkg, []string{"NotFound"})
+		tt.AssertErrContains(t, err, "NotFound", "not found")
+
+		_, err = generate(pkg, []string{"NotAStruct"})
+		tt.AssertErrContains(t, err, "NotAStruct", "struct")
+
+		err = run([]string{"-dir", dir})
+		tt.AssertErrContains(t, err, "missing", "-type")
+
+		_, err = loadPackage(t.TempDir())
+		tt.AssertErrContains(t, err, "no Go files")
+	})
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(configSource), 0644)
+	tt.AssertNoErr(t, err)
+
+	err = run([]string{"-dir", dir, "-type", "Config"})
+	tt.AssertNoErr(t, err)
+
+	b, err := os.ReadFile(filepath.Join(dir, "config_structi.go"))
+	tt.AssertNoErr(t, err)
+	tt.AssertTrue(t, strings.Contains(string(b), "(*Config)(nil)"), "unexpected output: %s", string(b))
+}
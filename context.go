@@ -0,0 +1,38 @@
+package structi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ContextIteratorFunc works like IteratorFunc but also
+// receives the context passed to ForEachContext()
+type ContextIteratorFunc func(ctx context.Context, field Field) error
+
+// ForEachContext works like ForEach but stops the iteration as soon as
+// the context is cancelled, returning an error that wraps ctx.Err() and
+// describes the field that was being processed at the time.
+func ForEachContext(ctx context.Context, targetStruct any, iterate ContextIteratorFunc) error {
+	return ForEach(targetStruct, func(field Field) error {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		err = iterate(ctx, field)
+		if err == nil {
+			return nil
+		}
+
+		// Make sure the error wraps ctx.Err() even if the
+		// iterator returned some other error after the
+		// context was cancelled:
+		ctxErr := ctx.Err()
+		if ctxErr != nil && !errors.Is(err, ctxErr) {
+			return fmt.Errorf("%w: %w", ctxErr, err)
+		}
+
+		return err
+	})
+}
@@ -0,0 +1,82 @@
+package structi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func TestForEachContext(t *testing.T) {
+	t.Run("should pass the context to the iterator", func(t *testing.T) {
+		type ctxKey struct{}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "fakeValue")
+
+		var output struct {
+			Attr1 string
+			Attr2 string
+		}
+		err := structi.ForEachContext(ctx, &output, func(ctx context.Context, field structi.Field) error {
+			return field.Set(ctx.Value(ctxKey{}))
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, "fakeValue")
+		tt.AssertEqual(t, output.Attr2, "fakeValue")
+	})
+
+	t.Run("should stop as soon as the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var output struct {
+			Attr1 int
+			Attr2 int
+			Attr3 int
+		}
+		visited := []string{}
+		err := structi.ForEachContext(ctx, &output, func(ctx context.Context, field structi.Field) error {
+			visited = append(visited, field.Name)
+			if field.Name == "Attr1" {
+				cancel()
+			}
+			return nil
+		})
+		tt.AssertEqual(t, errors.Is(err, context.Canceled), true)
+		tt.AssertErrContains(t, err, "Attr2", "int", "canceled")
+		tt.AssertEqual(t, visited, []string{"Attr1"})
+	})
+
+	t.Run("should wrap both ctx.Err() and the errors returned by the iterator", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var output struct {
+			Attr1 int
+		}
+		fakeErr := fmt.Errorf("fakeErrMsg")
+		err := structi.ForEachContext(ctx, &output, func(ctx context.Context, field structi.Field) error {
+			cancel()
+			return fakeErr
+		})
+		tt.AssertEqual(t, errors.Is(err, context.Canceled), true)
+		tt.AssertEqual(t, errors.Is(err, fakeErr), true)
+		tt.AssertErrContains(t, err, "Attr1", "canceled", "fakeErrMsg")
+	})
+
+	t.Run("should not iterate if the context is already done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var output struct {
+			Attr1 int
+		}
+		err := structi.ForEachContext(ctx, &output, func(ctx context.Context, field structi.Field) error {
+			return fmt.Errorf("should not run")
+		})
+		tt.AssertEqual(t, errors.Is(err, context.Canceled), true)
+		tt.AssertErrContains(t, err, "Attr1")
+	})
+}
@@ -0,0 +1,373 @@
+package structi
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/vingarcia/structi/internal/types"
+	"github.com/vingarcia/structi/tags"
+)
+
+// CopyOptions allows the user to customize the behavior of CopyInto()
+type CopyOptions struct {
+	// Tag is the name of the tag used for matching the fields
+	// of different struct types, e.g. "json" or "map".
+	//
+	// Fields without this tag are matched by name, and
+	// if left empty all fields are matched by name.
+	Tag string
+}
+
+// Clone returns a deep copy of the input value.
+//
+// Pointers, slices, maps, arrays and interfaces are all copied
+// recursively, and pointers shared between different parts of the
+// input (including cyclic references) are also shared on the copy.
+//
+// Note that unexported fields are copied by value, so if
+// they contain pointers these will be shared with the input.
+func Clone[T any](value T) (T, error) {
+	var clone T
+	err := newCopier(CopyOptions{}).deepCopy(
+		reflect.ValueOf(&clone).Elem(),
+		reflect.ValueOf(&value).Elem(),
+	)
+	return clone, err
+}
+
+// CopyInto deep copies the `src` value into the value pointed by `dst`.
+//
+// If the types of `src` and `dst` are different it will copy structs
+// by matching their field names (or the tag informed on the options)
+// and convert the other values using the same rules of Field.Set().
+func CopyInto(dst any, src any, opts ...CopyOptions) error {
+	var o CopyOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() {
+		return fmt.Errorf("expected non-nil pointer as destination, but got: %T", dst)
+	}
+
+	srcValue := reflect.ValueOf(src)
+	if !srcValue.IsValid() {
+		dstValue.Elem().Set(reflect.Zero(dstValue.Elem().Type()))
+		return nil
+	}
+
+	return newCopier(o).copy("", dstValue.Elem(), srcValue)
+}
+
+type copier struct {
+	tag string
+
+	// copies maps the pointers and maps already copied to their
+	// copies so that shared and cyclic references are preserved.
+	copies map[copyKey]reflect.Value
+}
+
+type copyKey struct {
+	ptr     uintptr
+	dstType reflect.Type
+}
+
+func newCopier(opts CopyOptions) copier {
+	return copier{
+		tag:    opts.Tag,
+		copies: map[copyKey]reflect.Value{},
+	}
+}
+
+// deepCopy copies values of the same type recursively.
+func (c copier) deepCopy(dst reflect.Value, src reflect.Value) error {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		key := copyKey{src.Pointer(), dst.Type()}
+		if cp, found := c.copies[key]; found {
+			dst.Set(cp)
+			return nil
+		}
+
+		cp := reflect.New(src.Type().Elem())
+		c.copies[key] = cp
+		err := c.deepCopy(cp.Elem(), src.Elem())
+		if err != nil {
+			return err
+		}
+		dst.Set(cp)
+
+	case reflect.Struct:
+		_, fields, err := getStructInfoForType(reflect.PointerTo(src.Type()))
+		if err != nil {
+			return err
+		}
+
+		// This copies the unexported fields:
+		dst.Set(src)
+		for _, field := range fields {
+			err := c.deepCopy(dst.Field(field.idx), src.Field(field.idx))
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		cp := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			err := c.deepCopy(cp.Index(i), src.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		dst.Set(cp)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			err := c.deepCopy(dst.Index(i), src.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		key := copyKey{src.Pointer(), dst.Type()}
+		if cp, found := c.copies[key]; found {
+			dst.Set(cp)
+			return nil
+		}
+
+		cp := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.copies[key] = cp
+		iter := src.MapRange()
+		for iter.Next() {
+			value := reflect.New(src.Type().Elem()).Elem()
+			err := c.deepCopy(value, iter.Value())
+			if err != nil {
+				return err
+			}
+			cp.SetMapIndex(iter.Key(), value)
+		}
+		dst.Set(cp)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		cp := reflect.New(src.Elem().Type()).Elem()
+		err := c.deepCopy(cp, src.Elem())
+		if err != nil {
+			return err
+		}
+		dst.Set(cp)
+
+	default:
+		dst.Set(src)
+	}
+
+	return nil
+}
+
+// copy copies values of possibly different types recursively,
+// converting them when necessary.
+func (c copier) copy(path string, dst reflect.Value, src reflect.Value) error {
+	if src.Kind() == reflect.Interface && dst.Kind() != reflect.Interface {
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		src = src.Elem()
+	}
+
+	if src.Type() == dst.Type() {
+		return c.deepCopy(dst, src)
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if src.Kind() == reflect.Ptr && src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		if src.Kind() == reflect.Ptr {
+			key := copyKey{src.Pointer(), dst.Type()}
+			if cp, found := c.copies[key]; found {
+				dst.Set(cp)
+				return nil
+			}
+
+			cp := reflect.New(dst.Type().Elem())
+			c.copies[key] = cp
+			err := c.copy(path, cp.Elem(), src.Elem())
+			if err != nil {
+				return err
+			}
+			dst.Set(cp)
+			return nil
+		}
+
+		cp := reflect.New(dst.Type().Elem())
+		err := c.copy(path, cp.Elem(), src)
+		if err != nil {
+			return err
+		}
+		dst.Set(cp)
+		return nil
+	}
+
+	if src.Kind() == reflect.Ptr {
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		return c.copy(path, dst, src.Elem())
+	}
+
+	switch {
+	case dst.Kind() == reflect.Interface && src.Type().Implements(dst.Type()):
+		cp := reflect.New(src.Type()).Elem()
+		err := c.deepCopy(cp, src)
+		if err != nil {
+			return err
+		}
+		dst.Set(cp)
+		return nil
+
+	case dst.Kind() == reflect.Struct && src.Kind() == reflect.Struct:
+		return c.copyStruct(path, dst, src)
+
+	case dst.Kind() == reflect.Slice && (src.Kind() == reflect.Slice || src.Kind() == reflect.Array):
+		if src.Kind() == reflect.Slice && src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		cp := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			err := c.copy(fmt.Sprintf("%s[%d]", path, i), cp.Index(i), src.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		dst.Set(cp)
+		return nil
+
+	case dst.Kind() == reflect.Array && (src.Kind() == reflect.Slice || src.Kind() == reflect.Array):
+		if src.Len() > dst.Len() {
+			return fmt.Errorf("error copying %s: cannot copy %d items into array of type %v", path, src.Len(), dst.Type())
+		}
+
+		for i := 0; i < src.Len(); i++ {
+			err := c.copy(fmt.Sprintf("%s[%d]", path, i), dst.Index(i), src.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case dst.Kind() == reflect.Map && src.Kind() == reflect.Map:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		cp := reflect.MakeMapWithSize(dst.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			itemPath := fmt.Sprintf("%s[%v]", path, iter.Key())
+
+			key := reflect.New(dst.Type().Key()).Elem()
+			err := c.copy(itemPath, key, iter.Key())
+			if err != nil {
+				return err
+			}
+
+			value := reflect.New(dst.Type().Elem()).Elem()
+			err = c.copy(itemPath, value, iter.Value())
+			if err != nil {
+				return err
+			}
+
+			cp.SetMapIndex(key, value)
+		}
+		dst.Set(cp)
+		return nil
+	}
+
+	convertedValue, err := types.NewConverter(src).Convert(dst.Type())
+	if err != nil {
+		return fmt.Errorf("error copying %s: %w", path, err)
+	}
+	dst.Set(convertedValue)
+
+	return nil
+}
+
+func (c copier) copyStruct(path string, dst reflect.Value, src reflect.Value) error {
+	_, dstFields, err := getStructInfoForType(reflect.PointerTo(dst.Type()))
+	if err != nil {
+		return err
+	}
+
+	_, srcFields, err := getStructInfoForType(reflect.PointerTo(src.Type()))
+	if err != nil {
+		return err
+	}
+
+	srcFieldsByKey := map[string]fieldInfo{}
+	for _, field := range srcFields {
+		key := c.fieldKey(field)
+		if key != "-" {
+			srcFieldsByKey[key] = field
+		}
+	}
+
+	for _, dstField := range dstFields {
+		key := c.fieldKey(dstField)
+		srcField, found := srcFieldsByKey[key]
+		if key == "-" || !found {
+			continue
+		}
+
+		err := c.copy(
+			joinPath(path, dstField.Name),
+			dst.Field(dstField.idx),
+			src.Field(srcField.idx),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c copier) fieldKey(field fieldInfo) string {
+	if c.tag == "" {
+		return field.Name
+	}
+
+	name, _ := tags.SplitOptions(field.Tags[c.tag])
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
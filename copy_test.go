@@ -0,0 +1,209 @@
+package structi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func TestClone(t *testing.T) {
+	type Address struct {
+		City  string
+		Lines []string
+	}
+
+	type User struct {
+		Name      string
+		Address   *Address
+		Tags      []string
+		Meta      map[string]any
+		Scores    [2]int
+		CreatedAt time.Time
+		Any       any
+	}
+
+	t.Run("should deep copy nested values", func(t *testing.T) {
+		u := &User{
+			Name: "fakeName",
+			Address: &Address{
+				City:  "fakeCity",
+				Lines: []string{"line1"},
+			},
+			Tags:      []string{"a", "b"},
+			Meta:      map[string]any{"key": []int{1, 2}},
+			Scores:    [2]int{1, 2},
+			CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			Any:       &Address{City: "otherCity"},
+		}
+
+		clone, err := structi.Clone(u)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, clone, u)
+
+		clone.Address.City = "changedCity"
+		clone.Address.Lines[0] = "changedLine"
+		clone.Tags[0] = "changedTag"
+		clone.Meta["key"].([]int)[0] = 42
+		clone.Any.(*Address).City = "changedCity"
+
+		tt.AssertEqual(t, u.Address.City, "fakeCity")
+		tt.AssertEqual(t, u.Address.Lines, []string{"line1"})
+		tt.AssertEqual(t, u.Tags, []string{"a", "b"})
+		tt.AssertEqual(t, u.Meta["key"], []int{1, 2})
+		tt.AssertEqual(t, u.Any.(*Address).City, "otherCity")
+	})
+
+	t.Run("should keep nil values as nil", func(t *testing.T) {
+		clone, err := structi.Clone(User{})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, clone, User{})
+	})
+
+	t.Run("should preserve shared and cyclic references", func(t *testing.T) {
+		type Node struct {
+			Value    int
+			Parent   *Node
+			Children []*Node
+		}
+
+		root := &Node{Value: 1}
+		child := &Node{Value: 2, Parent: root}
+		root.Children = []*Node{child, child}
+
+		clone, err := structi.Clone(root)
+		tt.AssertNoErr(t, err)
+		tt.AssertTrue(t, clone != root)
+		tt.AssertTrue(t, clone.Children[0] != child)
+		tt.AssertTrue(t, clone.Children[0] == clone.Children[1])
+		tt.AssertTrue(t, clone.Children[0].Parent == clone)
+		tt.AssertEqual(t, clone.Children[0].Value, 2)
+	})
+}
+
+func TestCopyInto(t *testing.T) {
+	t.Run("should copy values of the same type", func(t *testing.T) {
+		type User struct {
+			Name string
+			Tags []string
+		}
+
+		src := User{Name: "fakeName", Tags: []string{"a"}}
+		var dst User
+		err := structi.CopyInto(&dst, &src)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, dst, src)
+
+		dst.Tags[0] = "changed"
+		tt.AssertEqual(t, src.Tags, []string{"a"})
+	})
+
+	t.Run("should copy between different struct types by field name", func(t *testing.T) {
+		type Address struct {
+			City string
+		}
+		type User struct {
+			ID      int
+			Name    string
+			Age     int
+			Address *Address
+			Scores  []int
+		}
+
+		type AddressDTO struct {
+			City    string
+			Country string
+		}
+		type UserDTO struct {
+			Name    string
+			Age     float64
+			Address AddressDTO
+			Scores  []float64
+			Extra   string
+		}
+
+		var dto UserDTO
+		err := structi.CopyInto(&dto, User{
+			ID:      1,
+			Name:    "fakeName",
+			Age:     42,
+			Address: &Address{City: "fakeCity"},
+			Scores:  []int{1, 2},
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, dto, UserDTO{
+			Name:    "fakeName",
+			Age:     42.0,
+			Address: AddressDTO{City: "fakeCity"},
+			Scores:  []float64{1, 2},
+		})
+	})
+
+	t.Run("should match fields by tag if a tag is provided", func(t *testing.T) {
+		type User struct {
+			FullName string `json:"name"`
+			Age      int    `json:"age,omitempty"`
+			Ignored  string `json:"-"`
+		}
+		type UserDTO struct {
+			Name    string `json:"name"`
+			Years   int    `json:"age"`
+			Ignored string `json:"-"`
+		}
+
+		var dto UserDTO
+		err := structi.CopyInto(&dto, User{
+			FullName: "fakeName",
+			Age:      42,
+			Ignored:  "ignored",
+		}, structi.CopyOptions{Tag: "json"})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, dto, UserDTO{
+			Name:  "fakeName",
+			Years: 42,
+		})
+	})
+
+	t.Run("should convert maps and arrays of different types", func(t *testing.T) {
+		var dst struct {
+			Map   map[string]float64
+			Array [3]int
+		}
+		err := structi.CopyInto(&dst, struct {
+			Map   map[string]int
+			Array []int
+		}{
+			Map:   map[string]int{"a": 1},
+			Array: []int{1, 2},
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, dst.Map, map[string]float64{"a": 1})
+		tt.AssertEqual(t, dst.Array, [3]int{1, 2, 0})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		type Address struct {
+			City int
+		}
+		var dst struct {
+			Address Address
+			Array   [1]int
+		}
+
+		err := structi.CopyInto(dst, struct{}{})
+		tt.AssertErrContains(t, err, "expected non-nil pointer")
+
+		err = structi.CopyInto(&dst, struct {
+			Address struct{ City string }
+		}{})
+		tt.AssertErrContains(t, err, "Address.City", "string", "int")
+
+		err = structi.CopyInto(&dst, struct {
+			Array []int
+		}{
+			Array: []int{1, 2},
+		})
+		tt.AssertErrContains(t, err, "Array", "2 items", "[1]int")
+	})
+}
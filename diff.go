@@ -0,0 +1,272 @@
+package structi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeKind describes how a value has changed between
+// the two inputs of the Diff() function.
+type ChangeKind string
+
+// These are all the kinds of changes the Diff() function might report.
+const (
+	Added    ChangeKind = "added"
+	Removed  ChangeKind = "removed"
+	Modified ChangeKind = "modified"
+)
+
+// Change describes a single difference found by the Diff() function.
+//
+// The Path is built from the names of the struct fields, slice indexes
+// and map keys that lead to the changed value, e.g. `Address.Lines[1]`.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Old  any
+	New  any
+}
+
+// Diff compares two values of the same struct type recursively and
+// returns the list of changes that would transform `a` into `b`.
+//
+// Both arguments can be either structs or pointers to structs,
+// unexported fields are not compared and fields tagged with
+// `diff:"-"` are ignored.
+func Diff(a any, b any) ([]Change, error) {
+	va := reflect.ValueOf(a)
+	vb := reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() {
+		return nil, fmt.Errorf("expected struct or struct pointer but got: %T and %T", a, b)
+	}
+
+	if va.Type() != vb.Type() {
+		return nil, fmt.Errorf("can only diff values of the same type, but got: %v and %v", va.Type(), vb.Type())
+	}
+
+	t := va.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can only diff structs, but got: %v", va.Type())
+	}
+
+	d := differ{
+		visiting: map[visitedPair]bool{},
+	}
+	err := d.diff("", va, vb)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.changes, nil
+}
+
+type differ struct {
+	changes []Change
+
+	// visiting keeps track of the pairs of pointers, maps and slices on
+	// the current path so that cyclic values don't cause infinite loops,
+	// values shared by different paths are still compared on each of them.
+	visiting map[visitedPair]bool
+}
+
+// enter marks the pair of values as being compared on the current path,
+// it returns false if they already are, and a function for unmarking them.
+func (d *differ) enter(a reflect.Value, b reflect.Value) (bool, func()) {
+	key := visitedPair{a: a.Pointer(), b: b.Pointer(), t: a.Type()}
+	if a.Kind() == reflect.Slice {
+		key.len = a.Len()
+	}
+
+	if d.visiting[key] {
+		return false, nil
+	}
+	d.visiting[key] = true
+	return true, func() { delete(d.visiting, key) }
+}
+
+func (d *differ) diff(path string, a reflect.Value, b reflect.Value) error {
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() && b.IsNil() {
+			return nil
+		}
+		if a.IsNil() {
+			d.add(path, Added, nil, b.Elem().Interface())
+			return nil
+		}
+		if b.IsNil() {
+			d.add(path, Removed, a.Elem().Interface(), nil)
+			return nil
+		}
+
+		ok, leave := d.enter(a, b)
+		if !ok {
+			return nil
+		}
+		defer leave()
+
+		return d.diff(path, a.Elem(), b.Elem())
+
+	case reflect.Interface:
+		if a.IsNil() && b.IsNil() {
+			return nil
+		}
+		if a.IsNil() {
+			d.add(path, Added, nil, b.Elem().Interface())
+			return nil
+		}
+		if b.IsNil() {
+			d.add(path, Removed, a.Elem().Interface(), nil)
+			return nil
+		}
+
+		if a.Elem().Type() != b.Elem().Type() {
+			d.add(path, Modified, a.Elem().Interface(), b.Elem().Interface())
+			return nil
+		}
+
+		return d.diff(path, a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		_, fields, err := getStructInfoForType(reflect.PointerTo(a.Type()))
+		if err != nil {
+			return err
+		}
+
+		// Structs with no exported fields like time.Time are
+		// compared as a single value:
+		if len(fields) == 0 && a.NumField() > 0 {
+			d.diffLeaf(path, a, b)
+			return nil
+		}
+
+		for _, field := range fields {
+			if field.Tags["diff"] == "-" {
+				continue
+			}
+
+			err := d.diff(joinPath(path, field.Name), a.Field(field.idx), b.Field(field.idx))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice {
+			ok, leave := d.enter(a, b)
+			if !ok {
+				return nil
+			}
+			defer leave()
+		}
+
+		for i := 0; i < a.Len() || i < b.Len(); i++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i >= a.Len() {
+				d.add(itemPath, Added, nil, b.Index(i).Interface())
+				continue
+			}
+			if i >= b.Len() {
+				d.add(itemPath, Removed, a.Index(i).Interface(), nil)
+				continue
+			}
+
+			err := d.diff(itemPath, a.Index(i), b.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		ok, leave := d.enter(a, b)
+		if !ok {
+			return nil
+		}
+		defer leave()
+
+		for _, key := range sortedMapKeys(a, b) {
+			itemPath := fmt.Sprintf("%s[%v]", path, key)
+			aItem := a.MapIndex(key)
+			bItem := b.MapIndex(key)
+			if !aItem.IsValid() {
+				d.add(itemPath, Added, nil, bItem.Interface())
+				continue
+			}
+			if !bItem.IsValid() {
+				d.add(itemPath, Removed, aItem.Interface(), nil)
+				continue
+			}
+
+			err := d.diff(itemPath, aItem, bItem)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	d.diffLeaf(path, a, b)
+	return nil
+}
+
+func (d *differ) diffLeaf(path string, a reflect.Value, b reflect.Value) {
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		d.add(path, Modified, a.Interface(), b.Interface())
+	}
+}
+
+func (d *differ) add(path string, kind ChangeKind, oldValue any, newValue any) {
+	d.changes = append(d.changes, Change{
+		Path: path,
+		Kind: kind,
+		Old:  oldValue,
+		New:  newValue,
+	})
+}
+
+// sortedMapKeys returns the union of the keys of both maps
+// sorted by their string representation so that the
+// output of the functions using it is deterministic.
+//
+// It doesn't call Interface() on the keys since maps reached
+// through unexported fields have read-only keys.
+func sortedMapKeys(maps ...reflect.Value) []reflect.Value {
+	keys := []reflect.Value{}
+	for i, m := range maps {
+		iter := m.MapRange()
+		for iter.Next() {
+			key := iter.Key()
+			if isKeyOfAny(key, maps[:i]) {
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+func isKeyOfAny(key reflect.Value, maps []reflect.Value) bool {
+	for _, m := range maps {
+		if m.MapIndex(key).IsValid() {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
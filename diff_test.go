@@ -0,0 +1,246 @@
+package structi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func TestDiff(t *testing.T) {
+	type Address struct {
+		Street string
+		City   string
+	}
+
+	type User struct {
+		ID        int `diff:"-"`
+		Name      string
+		Address   Address
+		Manager   *Address
+		Tags      []string
+		Meta      map[string]any
+		UpdatedAt time.Time
+		Any       any
+
+		unexported int
+	}
+
+	t.Run("should return no changes for equal structs", func(t *testing.T) {
+		u := User{
+			Name:    "fakeName",
+			Address: Address{Street: "fakeStreet"},
+			Manager: &Address{City: "fakeCity"},
+			Tags:    []string{"a", "b"},
+			Meta:    map[string]any{"key": "value"},
+		}
+		u2 := u
+		u2.Manager = &Address{City: "fakeCity"}
+
+		changes, err := structi.Diff(u, u2)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, len(changes), 0)
+	})
+
+	t.Run("should report modified fields recursively", func(t *testing.T) {
+		changes, err := structi.Diff(
+			&User{Name: "oldName", Address: Address{City: "oldCity"}},
+			&User{Name: "newName", Address: Address{City: "newCity"}},
+		)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Name", Kind: structi.Modified, Old: "oldName", New: "newName"},
+			{Path: "Address.City", Kind: structi.Modified, Old: "oldCity", New: "newCity"},
+		})
+	})
+
+	t.Run("should report added and removed pointers", func(t *testing.T) {
+		changes, err := structi.Diff(
+			User{},
+			User{Manager: &Address{City: "fakeCity"}},
+		)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Manager", Kind: structi.Added, Old: nil, New: Address{City: "fakeCity"}},
+		})
+
+		changes, err = structi.Diff(
+			User{Manager: &Address{City: "fakeCity"}},
+			User{},
+		)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Manager", Kind: structi.Removed, Old: Address{City: "fakeCity"}, New: nil},
+		})
+	})
+
+	t.Run("should compare slices item by item", func(t *testing.T) {
+		changes, err := structi.Diff(
+			User{Tags: []string{"a", "b", "c"}},
+			User{Tags: []string{"a", "x"}},
+		)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Tags[1]", Kind: structi.Modified, Old: "b", New: "x"},
+			{Path: "Tags[2]", Kind: structi.Removed, Old: "c", New: nil},
+		})
+
+		changes, err = structi.Diff(
+			User{},
+			User{Tags: []string{"a"}},
+		)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Tags[0]", Kind: structi.Added, Old: nil, New: "a"},
+		})
+	})
+
+	t.Run("should compare maps key by key in a deterministic order", func(t *testing.T) {
+		changes, err := structi.Diff(
+			User{Meta: map[string]any{"a": 1, "b": 2, "c": 3}},
+			User{Meta: map[string]any{"b": 2, "c": 4, "d": 5}},
+		)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Meta[a]", Kind: structi.Removed, Old: 1, New: nil},
+			{Path: "Meta[c]", Kind: structi.Modified, Old: 3, New: 4},
+			{Path: "Meta[d]", Kind: structi.Added, Old: nil, New: 5},
+		})
+	})
+
+	t.Run("should compare structs without exported fields as a single value", func(t *testing.T) {
+		t1 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		t2 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		changes, err := structi.Diff(User{UpdatedAt: t1}, User{UpdatedAt: t2})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "UpdatedAt", Kind: structi.Modified, Old: t1, New: t2},
+		})
+	})
+
+	t.Run("should compare the dynamic values of interface fields", func(t *testing.T) {
+		changes, err := structi.Diff(
+			User{Any: Address{City: "oldCity"}},
+			User{Any: Address{City: "newCity"}},
+		)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Any.City", Kind: structi.Modified, Old: "oldCity", New: "newCity"},
+		})
+
+		changes, err = structi.Diff(User{Any: 42}, User{Any: "42"})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Any", Kind: structi.Modified, Old: 42, New: "42"},
+		})
+	})
+
+	t.Run("should ignore fields tagged with diff:\"-\" and unexported fields", func(t *testing.T) {
+		changes, err := structi.Diff(
+			User{ID: 1, unexported: 1},
+			User{ID: 2, unexported: 2},
+		)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, len(changes), 0)
+	})
+
+	t.Run("should not loop forever on cyclic values", func(t *testing.T) {
+		type Node struct {
+			Value  int
+			Parent *Node
+		}
+
+		a := &Node{Value: 1}
+		a.Parent = a
+		b := &Node{Value: 2}
+		b.Parent = b
+
+		changes, err := structi.Diff(a, b)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Value", Kind: structi.Modified, Old: 1, New: 2},
+		})
+	})
+
+	t.Run("should not loop forever on cyclic maps and slices", func(t *testing.T) {
+		m1 := map[string]any{"value": 1}
+		m1["self"] = m1
+		m2 := map[string]any{"value": 2}
+		m2["self"] = m2
+		s1 := []any{1, nil}
+		s1[1] = s1
+		s2 := []any{2, nil}
+		s2[1] = s2
+
+		changes, err := structi.Diff(User{Meta: m1, Any: s1}, User{Meta: m2, Any: s2})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "Meta[value]", Kind: structi.Modified, Old: 1, New: 2},
+			{Path: "Any[0]", Kind: structi.Modified, Old: 1, New: 2},
+		})
+	})
+
+	t.Run("should compare pointers shared by different fields on each of them", func(t *testing.T) {
+		type Value struct {
+			V int
+		}
+		type Shared struct {
+			X *Value
+			Y *Value
+		}
+
+		p1 := &Value{V: 1}
+		p2 := &Value{V: 2}
+		changes, err := structi.Diff(Shared{X: p1, Y: p1}, Shared{X: p2, Y: p2})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, changes, []structi.Change{
+			{Path: "X.V", Kind: structi.Modified, Old: 1, New: 2},
+			{Path: "Y.V", Kind: structi.Modified, Old: 1, New: 2},
+		})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		tests := []struct {
+			desc               string
+			a                  any
+			b                  any
+			expectErrToContain []string
+		}{
+			{
+				desc:               "different types",
+				a:                  User{},
+				b:                  Address{},
+				expectErrToContain: []string{"same type", "User", "Address"},
+			},
+			{
+				desc:               "not a struct",
+				a:                  42,
+				b:                  43,
+				expectErrToContain: []string{"can only diff structs", "int"},
+			},
+			{
+				desc:               "nil inputs",
+				a:                  nil,
+				b:                  User{},
+				expectErrToContain: []string{"expected struct", "nil"},
+			},
+			{
+				desc: "malformed tags",
+				a: struct {
+					Attr1 string `line_break:attr1"`
+				}{},
+				b: struct {
+					Attr1 string `line_break:attr1"`
+				}{},
+				expectErrToContain: []string{"malformed tag"},
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.desc, func(t *testing.T) {
+				_, err := structi.Diff(test.a, test.b)
+				tt.AssertErrContains(t, err, test.expectErrToContain...)
+			})
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package docgen
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/vingarcia/structi"
+	"github.com/vingarcia/structi/tags"
+)
+
+// Setting describes a single configuration setting,
+// i.e. one row of the generated documentation.
+type Setting struct {
+	Path        string
+	EnvVar      string
+	Flag        string
+	Type        string
+	Default     string
+	Required    bool
+	Description string
+}
+
+// Options allows the user to customize the tags read by this package
+type Options struct {
+	// EnvTag is "env" by default
+	EnvTag string
+
+	// FlagTag is "flag" by default
+	FlagTag string
+
+	// DefaultTag is "default" by default
+	DefaultTag string
+
+	// DescriptionTag is "description" by default
+	DescriptionTag string
+}
+
+// Collect lists all the settings of the input config struct type
+// (or pointer to struct type), including the ones from nested structs.
+//
+// The path of each setting is built from the names of the fields
+// that lead to it, e.g. `DB.Host`, and the fields of embedded
+// structs are listed as if they were declared on the parent struct.
+func Collect(t reflect.Type, opts ...Options) ([]Setting, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return CollectFrom(reflectStruct{t: t}, opts...)
+}
+
+// StructType describes a struct type whose settings can be listed with
+// CollectFrom(), which allows collecting settings from sources other
+// than reflection, e.g. from type checked source code.
+//
+// Implementations must be comparable, and two values must only be equal
+// if they describe the same type, since they are used for detecting
+// recursive types.
+type StructType interface {
+	// Fields returns the exported fields of the struct
+	Fields() ([]StructField, error)
+}
+
+// StructField describes a single field of a StructType
+type StructField struct {
+	Name     string
+	Embedded bool
+
+	// TypeName is the name of the type as it
+	// should appear on the documentation.
+	TypeName string
+	Tags     map[string]string
+
+	// Struct should be set for fields of struct types (or pointers to them)
+	// whose fields should be listed as separate settings, if nil the field
+	// is listed as a single setting.
+	Struct StructType
+}
+
+// CollectFrom works like Collect() but reads
+// the fields from any StructType implementation.
+func CollectFrom(st StructType, opts ...Options) ([]Setting, error) {
+	o := withDefaults(opts)
+
+	settings := []Setting{}
+	err := collect(&settings, "", st, o, map[StructType]bool{})
+	return settings, err
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func collect(settings *[]Setting, path string, st StructType, o Options, visiting map[StructType]bool) error {
+	fields, err := st.Fields()
+	if err != nil {
+		return err
+	}
+
+	// Recursive types are listed as a single setting
+	// on the second time they appear on the path:
+	visiting[st] = true
+	defer delete(visiting, st)
+
+	for _, field := range fields {
+		fieldPath := path
+		if !field.Embedded {
+			fieldPath = joinPath(path, field.Name)
+		}
+
+		if field.Struct != nil && !visiting[field.Struct] {
+			err := collect(settings, fieldPath, field.Struct, o, visiting)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		*settings = append(*settings, NewSetting(fieldPath, field.TypeName, field.Tags, o))
+	}
+
+	return nil
+}
+
+// reflectStruct implements the StructType interface using reflection
+type reflectStruct struct {
+	t reflect.Type
+}
+
+func (r reflectStruct) Fields() ([]StructField, error) {
+	info, err := structi.GetStructInfo(r.t)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []StructField{}
+	for _, field := range info.Fields {
+		elemType := field.Type
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+
+		var st StructType
+		if elemType.Kind() == reflect.Struct && elemType != timeType {
+			st = reflectStruct{t: elemType}
+		}
+
+		fields = append(fields, StructField{
+			Name:     field.Name,
+			Embedded: field.IsEmbeded,
+			TypeName: field.Type.String(),
+			Tags:     field.Tags,
+			Struct:   st,
+		})
+	}
+
+	return fields, nil
+}
+
+// NewSetting builds a Setting from the tags of a struct field.
+func NewSetting(path string, typeName string, fieldTags map[string]string, opts ...Options) Setting {
+	o := withDefaults(opts)
+
+	envVar, _ := tags.SplitOptions(fieldTags[o.EnvTag])
+	flag, _ := tags.SplitOptions(fieldTags[o.FlagTag])
+	return Setting{
+		Path:        path,
+		EnvVar:      envVar,
+		Flag:        flag,
+		Type:        typeName,
+		Default:     fieldTags[o.DefaultTag],
+		Required:    fieldTags["required"] == "true" || strings.Contains(","+fieldTags["validate"]+",", ",required,"),
+		Description: fieldTags[o.DescriptionTag],
+	}
+}
+
+// Markdown renders the settings as a markdown table
+func Markdown(w io.Writer, settings []Setting) error {
+	lines := []string{
+		"| Path | Env Var | Flag | Type | Default | Required | Description |",
+		"| ---- | ------- | ---- | ---- | ------- | -------- | ----------- |",
+	}
+	for _, s := range settings {
+		required := "no"
+		if s.Required {
+			required = "yes"
+		}
+
+		lines = append(lines, fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |",
+			markdownCode(s.Path),
+			markdownCode(s.EnvVar),
+			markdownCode(s.Flag),
+			markdownCode(s.Type),
+			markdownCode(s.Default),
+			required,
+			markdownText(s.Description),
+		))
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + markdownText(s) + "`"
+}
+
+func markdownText(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+var htmlTemplate = template.Must(template.New("settings").Parse(`<table>
+  <thead>
+    <tr>
+      <th>Path</th>
+      <th>Env Var</th>
+      <th>Flag</th>
+      <th>Type</th>
+      <th>Default</th>
+      <th>Required</th>
+      <th>Description</th>
+    </tr>
+  </thead>
+  <tbody>
+{{- range .}}
+    <tr>
+      <td><code>{{.Path}}</code></td>
+      <td>{{if .EnvVar}}<code>{{.EnvVar}}</code>{{end}}</td>
+      <td>{{if .Flag}}<code>{{.Flag}}</code>{{end}}</td>
+      <td><code>{{.Type}}</code></td>
+      <td>{{if .Default}}<code>{{.Default}}</code>{{end}}</td>
+      <td>{{if .Required}}yes{{else}}no{{end}}</td>
+      <td>{{.Description}}</td>
+    </tr>
+{{- end}}
+  </tbody>
+</table>
+`))
+
+// HTML renders the settings as an HTML table
+func HTML(w io.Writer, settings []Setting) error {
+	return htmlTemplate.Execute(w, settings)
+}
+
+func withDefaults(opts []Options) Options {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.EnvTag == "" {
+		o.EnvTag = "env"
+	}
+	if o.FlagTag == "" {
+		o.FlagTag = "flag"
+	}
+	if o.DefaultTag == "" {
+		o.DefaultTag = "default"
+	}
+	if o.DescriptionTag == "" {
+		o.DescriptionTag = "description"
+	}
+	return o
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
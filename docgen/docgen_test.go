@@ -0,0 +1,113 @@
+package docgen_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi/docgen"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+type Base struct {
+	Debug bool `env:"DEBUG" flag:"debug" description:"Enables debug logs"`
+}
+
+type DBConfig struct {
+	Host    string        `env:"DB_HOST" flag:"db-host" default:"localhost" description:"The database host"`
+	Timeout time.Duration `env:"DB_TIMEOUT" default:"5s"`
+}
+
+type Config struct {
+	Base
+
+	Name    string    `env:"NAME" validate:"required" description:"The service | name"`
+	DB      DBConfig  `description:"ignored for nested structs"`
+	Replica *DBConfig `env:"REPLICA"`
+	Started time.Time `required:"true"`
+	Parent  *Config
+}
+
+func TestCollect(t *testing.T) {
+	settings, err := docgen.Collect(reflect.TypeOf(&Config{}))
+	tt.AssertNoErr(t, err)
+	tt.AssertEqual(t, settings, []docgen.Setting{
+		{Path: "Debug", EnvVar: "DEBUG", Flag: "debug", Type: "bool", Description: "Enables debug logs"},
+		{Path: "Name", EnvVar: "NAME", Type: "string", Required: true, Description: "The service | name"},
+		{Path: "DB.Host", EnvVar: "DB_HOST", Flag: "db-host", Type: "string", Default: "localhost", Description: "The database host"},
+		{Path: "DB.Timeout", EnvVar: "DB_TIMEOUT", Type: "time.Duration", Default: "5s"},
+		{Path: "Replica.Host", EnvVar: "DB_HOST", Flag: "db-host", Type: "string", Default: "localhost", Description: "The database host"},
+		{Path: "Replica.Timeout", EnvVar: "DB_TIMEOUT", Type: "time.Duration", Default: "5s"},
+		{Path: "Started", Type: "time.Time", Required: true},
+		{Path: "Parent", Type: "*docgen_test.Config"},
+	})
+
+	t.Run("should use the tags from the options", func(t *testing.T) {
+		settings, err := docgen.Collect(reflect.TypeOf(struct {
+			Port int `environment:"PORT" cli:"port" default_value:"80" desc:"The port"`
+		}{}), docgen.Options{
+			EnvTag:         "environment",
+			FlagTag:        "cli",
+			DefaultTag:     "default_value",
+			DescriptionTag: "desc",
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, settings, []docgen.Setting{
+			{Path: "Port", EnvVar: "PORT", Flag: "port", Type: "int", Default: "80", Description: "The port"},
+		})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		_, err := docgen.Collect(reflect.TypeOf(42))
+		tt.AssertErrContains(t, err, "can only get struct info from structs", "int")
+	})
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	err := docgen.Markdown(&buf, []docgen.Setting{
+		{Path: "Name", EnvVar: "NAME", Type: "string", Required: true, Description: "The service | name"},
+		{Path: "DB.Host", Flag: "db-host", Type: "string", Default: "localhost"},
+	})
+	tt.AssertNoErr(t, err)
+	tt.AssertEqual(t, buf.String(), ""+
+		"| Path | Env Var | Flag | Type | Default | Required | Description |\n"+
+		"| ---- | ------- | ---- | ---- | ------- | -------- | ----------- |\n"+
+		"| `Name` | `NAME` |  | `string` |  | yes | The service \\| name |\n"+
+		"| `DB.Host` |  | `db-host` | `string` | `localhost` | no |  |\n",
+	)
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	err := docgen.HTML(&buf, []docgen.Setting{
+		{Path: "Name", EnvVar: "NAME", Type: "string", Required: true, Description: "<b>escaped</b>"},
+	})
+	tt.AssertNoErr(t, err)
+	tt.AssertEqual(t, buf.String(), `<table>
+  <thead>
+    <tr>
+      <th>Path</th>
+      <th>Env Var</th>
+      <th>Flag</th>
+      <th>Type</th>
+      <th>Default</th>
+      <th>Required</th>
+      <th>Description</th>
+    </tr>
+  </thead>
+  <tbody>
+    <tr>
+      <td><code>Name</code></td>
+      <td><code>NAME</code></td>
+      <td></td>
+      <td><code>string</code></td>
+      <td></td>
+      <td>yes</td>
+      <td>&lt;b&gt;escaped&lt;/b&gt;</td>
+    </tr>
+  </tbody>
+</table>
+`)
+}
@@ -0,0 +1,280 @@
+package dotenv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vingarcia/structi"
+	"github.com/vingarcia/structi/internal/types"
+	"github.com/vingarcia/structi/tags"
+)
+
+// Load parses the .env input and fills the fields of the target struct
+// tagged with `env:"VAR_NAME"` with the values of the respective variables,
+// converting them to the type of each field, e.g.:
+//
+//	var config struct {
+//		Port    int           `env:"PORT"`
+//		Timeout time.Duration `env:"TIMEOUT"`
+//	}
+//	err := dotenv.Load(file, &config)
+//
+// Fields whose variables are not defined on the input are left untouched,
+// so default values can be set on the struct before calling Load().
+//
+// Slices are read from comma-separated lists, e.g. `HOSTS=host1,host2`,
+// and each item is converted to the type of the elements of the slice.
+func Load(r io.Reader, targetStruct any, opts ...ParseOptions) error {
+	vars, err := Parse(r, opts...)
+	if err != nil {
+		return err
+	}
+
+	return structi.ForEach(targetStruct, func(field structi.Field) error {
+		name := envName(field)
+		if name == "" {
+			return nil
+		}
+
+		value, found := vars[name]
+		if !found {
+			return nil
+		}
+
+		err := setValue(field, value)
+		if err != nil {
+			return fmt.Errorf("invalid value for variable %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// LoadFile works like Load() but reads the .env file from the given path
+func LoadFile(path string, targetStruct any, opts ...ParseOptions) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return Load(file, targetStruct, opts...)
+}
+
+// Write writes the fields of the target struct tagged with `env:"VAR_NAME"`
+// in the .env format, the `description` tag of each field is written as a
+// comment before the variable, e.g.:
+//
+//	# The port the server listens on
+//	PORT=8080
+//
+// Values are quoted and escaped as needed so that they
+// can be read back with Parse() or Load(). Slices are written as
+// comma-separated lists, so their items cannot contain commas.
+//
+// The target struct can be passed either by value or as a pointer.
+func Write(w io.Writer, targetStruct any) error {
+	// ForEach() only accepts pointers, so we iterate over a copy:
+	if v := reflect.ValueOf(targetStruct); v.Kind() == reflect.Struct {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		targetStruct = ptr.Interface()
+	}
+
+	buf := bufio.NewWriter(w)
+
+	isFirst := true
+	err := structi.ForEach(targetStruct, func(field structi.Field) error {
+		name := envName(field)
+		if name == "" {
+			return nil
+		}
+
+		value, err := formatValue(field)
+		if err != nil {
+			return fmt.Errorf("error formatting variable %s: %w", name, err)
+		}
+
+		if description := field.Tags["description"]; description != "" {
+			if !isFirst {
+				buf.WriteString("\n")
+			}
+			for _, line := range strings.Split(description, "\n") {
+				buf.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+			}
+		}
+		isFirst = false
+
+		buf.WriteString(name + "=" + quote(value) + "\n")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return buf.Flush()
+}
+
+// WriteFile works like Write() but writes the output
+// to the given path, replacing the file if it exists.
+func WriteFile(path string, targetStruct any) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = Write(file, targetStruct)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// envName returns the variable name read from the `env`
+// tag or an empty string if the field should be ignored.
+func envName(field structi.Field) string {
+	name, _ := tags.SplitOptions(field.Tags["env"])
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setValue(field structi.Field, value string) error {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		// Empty values are written by Write() for nil pointers:
+		if value == "" {
+			reflect.ValueOf(field.Value).Elem().Set(reflect.Zero(field.Type))
+			return nil
+		}
+		t = t.Elem()
+	}
+
+	if isList(t) {
+		// Empty values are written by Write() for empty slices:
+		if value == "" {
+			reflect.ValueOf(field.Value).Elem().Set(reflect.Zero(field.Type))
+			return nil
+		}
+
+		parts := strings.Split(value, ",")
+		items := make([]any, len(parts))
+		for i, part := range parts {
+			item, err := parseValue(t.Elem(), part)
+			if err != nil {
+				return fmt.Errorf("invalid item %d: %w", i, err)
+			}
+			items[i] = item
+		}
+		return field.Set(items)
+	}
+
+	parsedValue, err := parseValue(t, value)
+	if err != nil {
+		return err
+	}
+	return field.Set(parsedValue)
+}
+
+// parseValue converts the value to the numeric and boolean types,
+// all other types are converted from the string by field.Set()
+func parseValue(t reflect.Type, value string) (any, error) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+
+		// Durations are parsed from strings like "5s" by field.Set()
+		if t == durationType {
+			break
+		}
+
+		parsedValue, err := types.StringToType(t, value)
+		if err != nil {
+			return nil, err
+		}
+		return parsedValue.Interface(), nil
+	}
+
+	return value, nil
+}
+
+// isList reports whether the type is written as a comma-separated list,
+// byte slices are converted from strings as a single value.
+//
+// Rune slices are also lists since rune is an alias of int32
+// and so they can't be told apart from []int32.
+func isList(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+func formatValue(field structi.Field) (string, error) {
+	v := reflect.ValueOf(field.Value).Elem()
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	layout := field.Tags["layout"]
+	if !isList(v.Type()) {
+		return formatItem(v, layout)
+	}
+
+	items := make([]string, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		item, err := formatItem(v.Index(i), layout)
+		if err != nil {
+			return "", fmt.Errorf("invalid item %d: %w", i, err)
+		}
+
+		// Otherwise the item would be split in two by Load():
+		if strings.Contains(item, ",") {
+			return "", fmt.Errorf("invalid item %d: list items cannot contain commas, but got: '%s'", i, item)
+		}
+		items[i] = item
+	}
+	return strings.Join(items, ","), nil
+}
+
+func formatItem(v reflect.Value, layout string) (string, error) {
+	if v.Kind() == reflect.Bool {
+		return strconv.FormatBool(v.Bool()), nil
+	}
+
+	converter := types.NewConverter(v.Interface()).WithTimeLayout(layout)
+	s, err := converter.Convert(reflect.TypeOf(""))
+	if err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+// quote wraps the value in double quotes if it contains any
+// characters that would be interpreted differently by Parse()
+func quote(value string) string {
+	if !strings.ContainsAny(value, " \t\r\n#\"'\\$") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return `"` + replacer.Replace(value) + `"`
+}
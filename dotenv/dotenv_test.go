@@ -0,0 +1,315 @@
+package dotenv_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi/dotenv"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func noEnv(name string) (string, bool) {
+	return "", false
+}
+
+func TestParse(t *testing.T) {
+	t.Run("should parse the supported syntax", func(t *testing.T) {
+		vars, err := dotenv.Parse(strings.NewReader(strings.Join([]string{
+			"# A comment",
+			"",
+			"PLAIN=value",
+			"  SPACED = some value   # inline comment",
+			"export EXPORTED=1",
+			"HASH=foo#bar",
+			"EMPTY=",
+			`DOUBLE="line1\nline2 \"quoted\" \\ \$HOME # not a comment" # comment`,
+			`SINGLE='${PLAIN} \n'`,
+			`MULTILINE="first`,
+			`second"`,
+			`EXPANDED=${PLAIN}-${EXPORTED}`,
+			`EXPANDED_QUOTED="${SPACED}!"`,
+			`FROM_ENV=${FAKE_ENV_VAR}`,
+			`MISSING=[${NOT_DEFINED}]`,
+			"WINDOWS=crlf\r",
+		}, "\n")), dotenv.ParseOptions{
+			LookupEnv: func(name string) (string, bool) {
+				if name == "FAKE_ENV_VAR" {
+					return "fakeValue", true
+				}
+				return "", false
+			},
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, vars, map[string]string{
+			"PLAIN":           "value",
+			"SPACED":          "some value",
+			"EXPORTED":        "1",
+			"HASH":            "foo#bar",
+			"EMPTY":           "",
+			"DOUBLE":          "line1\nline2 \"quoted\" \\ $HOME # not a comment",
+			"SINGLE":          `${PLAIN} \n`,
+			"MULTILINE":       "first\nsecond",
+			"EXPANDED":        "value-1",
+			"EXPANDED_QUOTED": "some value!",
+			"FROM_ENV":        "fakeValue",
+			"MISSING":         "[]",
+			"WINDOWS":         "crlf",
+		})
+	})
+
+	t.Run("should report errors with the line number", func(t *testing.T) {
+		tests := []struct {
+			desc          string
+			input         string
+			expectErrMsgs []string
+		}{
+			{
+				desc:          "missing equal sign",
+				input:         "FOO=bar\nBAR baz",
+				expectErrMsgs: []string{"line 2", "expected '='", "BAR"},
+			},
+			{
+				desc:          "invalid variable name",
+				input:         "=bar",
+				expectErrMsgs: []string{"line 1", "expected variable name"},
+			},
+			{
+				desc:          "unclosed double quotes",
+				input:         "FOO=\"bar\n\nbaz",
+				expectErrMsgs: []string{"line 1", "FOO", "missing closing double quote"},
+			},
+			{
+				desc:          "unclosed single quotes",
+				input:         "\nFOO='bar",
+				expectErrMsgs: []string{"line 2", "FOO", "missing closing single quote"},
+			},
+			{
+				desc:          "text after the closing quote",
+				input:         `FOO="bar"baz`,
+				expectErrMsgs: []string{"line 1", "FOO", "after closing quote"},
+			},
+			{
+				desc:          "unclosed variable reference",
+				input:         "FOO=${BAR",
+				expectErrMsgs: []string{"line 1", "FOO", "missing closing brace"},
+			},
+			{
+				desc:          "invalid variable reference",
+				input:         `FOO="${BAR BAZ}"`,
+				expectErrMsgs: []string{"line 1", "FOO", "invalid variable reference"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.desc, func(t *testing.T) {
+				_, err := dotenv.Parse(strings.NewReader(test.input), dotenv.ParseOptions{
+					LookupEnv: noEnv,
+				})
+				tt.AssertErrContains(t, err, test.expectErrMsgs...)
+			})
+		}
+	})
+}
+
+type config struct {
+	Host      string        `env:"HOST" description:"The host of the server"`
+	Port      int           `env:"PORT" description:"The port of the server"`
+	Debug     bool          `env:"DEBUG"`
+	Ratio     *float64      `env:"RATIO"`
+	Timeout   time.Duration `env:"TIMEOUT" description:"How long to wait for responses,\ne.g. 5s or 1m"`
+	StartDate time.Time     `env:"START_DATE" layout:"2006-01-02"`
+	Greeting  string        `env:"GREETING"`
+	Internal  string        `env:"-"`
+	NoTag     string
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("should fill the tagged fields", func(t *testing.T) {
+		c := config{
+			Host:     "defaultHost",
+			Internal: "internal",
+			NoTag:    "noTag",
+		}
+		err := dotenv.Load(strings.NewReader(strings.Join([]string{
+			"PORT=8080",
+			"DEBUG=true",
+			"RATIO=0.5",
+			"TIMEOUT=5s",
+			"START_DATE=2024-01-02",
+			`GREETING="Hello\nWorld"`,
+			"NoTag=ignored",
+			"UNUSED=foo",
+		}, "\n")), &c)
+		tt.AssertNoErr(t, err)
+
+		ratio := 0.5
+		tt.AssertEqual(t, c, config{
+			Host:      "defaultHost",
+			Port:      8080,
+			Debug:     true,
+			Ratio:     &ratio,
+			Timeout:   5 * time.Second,
+			StartDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Greeting:  "Hello\nWorld",
+			Internal:  "internal",
+			NoTag:     "noTag",
+		})
+	})
+
+	t.Run("should read slices from comma-separated lists", func(t *testing.T) {
+		var c struct {
+			Hosts    []string        `env:"HOSTS"`
+			Ports    []int           `env:"PORTS"`
+			Timeouts []time.Duration `env:"TIMEOUTS"`
+			Empty    []string        `env:"EMPTY"`
+		}
+		c.Empty = []string{"default"}
+
+		err := dotenv.Load(strings.NewReader(strings.Join([]string{
+			"HOSTS=host1,host2",
+			"PORTS=80,443",
+			"TIMEOUTS=1s,1m",
+			"EMPTY=",
+		}, "\n")), &c)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, c.Hosts, []string{"host1", "host2"})
+		tt.AssertEqual(t, c.Ports, []int{80, 443})
+		tt.AssertEqual(t, c.Timeouts, []time.Duration{time.Second, time.Minute})
+		tt.AssertEqual(t, c.Empty, []string(nil))
+
+		err = dotenv.Load(strings.NewReader("PORTS=80,abc"), &c)
+		tt.AssertErrContains(t, err, "PORTS", "item 1", "invalid syntax")
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		var c config
+		err := dotenv.Load(strings.NewReader("PORT=abc"), &c)
+		tt.AssertErrContains(t, err, "Port", "PORT", "invalid syntax")
+
+		err = dotenv.Load(strings.NewReader("PORT"), &c)
+		tt.AssertErrContains(t, err, "line 1", "expected '='")
+
+		err = dotenv.Load(strings.NewReader(""), c)
+		tt.AssertErrContains(t, err, "expected struct pointer")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("should write the tagged fields with their descriptions", func(t *testing.T) {
+		c := config{
+			Host:      "localhost",
+			Port:      8080,
+			Timeout:   time.Minute,
+			StartDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Greeting:  "Hello \"${NAME}\"\n",
+			Internal:  "internal",
+		}
+
+		var buf bytes.Buffer
+		err := dotenv.Write(&buf, &c)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, buf.String(), strings.Join([]string{
+			"# The host of the server",
+			"HOST=localhost",
+			"",
+			"# The port of the server",
+			"PORT=8080",
+			"DEBUG=false",
+			"RATIO=",
+			"",
+			"# How long to wait for responses,",
+			"# e.g. 5s or 1m",
+			"TIMEOUT=1m0s",
+			"START_DATE=2024-01-02",
+			`GREETING="Hello \"\${NAME}\"\n"`,
+			"",
+		}, "\n"))
+
+		var loaded config
+		err = dotenv.Load(&buf, &loaded, dotenv.ParseOptions{LookupEnv: noEnv})
+		tt.AssertNoErr(t, err)
+		c.Internal = ""
+		tt.AssertEqual(t, loaded, c)
+	})
+
+	t.Run("should write and read files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".env")
+
+		ratio := 1.5
+		err := dotenv.WriteFile(path, &config{Host: "foo bar", Ratio: &ratio})
+		tt.AssertNoErr(t, err)
+
+		b, err := os.ReadFile(path)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, strings.Contains(string(b), `HOST="foo bar"`), true)
+
+		var loaded config
+		err = dotenv.LoadFile(path, &loaded)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, loaded.Host, "foo bar")
+		tt.AssertEqual(t, loaded.Ratio, &ratio)
+
+		err = dotenv.LoadFile(filepath.Join(t.TempDir(), "missing.env"), &loaded)
+		tt.AssertErrContains(t, err, "missing.env")
+	})
+
+	t.Run("should accept structs passed by value", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := dotenv.Write(&buf, config{Host: "localhost"})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, strings.Contains(buf.String(), "HOST=localhost\n"), true)
+	})
+
+	t.Run("should write slices as comma-separated lists", func(t *testing.T) {
+		type lists struct {
+			Hosts    []string        `env:"HOSTS"`
+			Ports    []int           `env:"PORTS"`
+			Codes    []int32         `env:"CODES"`
+			Timeouts []time.Duration `env:"TIMEOUTS"`
+			Empty    []string        `env:"EMPTY"`
+		}
+		c := lists{
+			Hosts:    []string{"host 1", "host2"},
+			Ports:    []int{80, 443},
+			Codes:    []int32{200, 404},
+			Timeouts: []time.Duration{time.Second},
+		}
+
+		var buf bytes.Buffer
+		err := dotenv.Write(&buf, &c)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, buf.String(), strings.Join([]string{
+			`HOSTS="host 1,host2"`,
+			"PORTS=80,443",
+			"CODES=200,404",
+			"TIMEOUTS=1s",
+			"EMPTY=",
+			"",
+		}, "\n"))
+
+		var loaded lists
+		err = dotenv.Load(&buf, &loaded)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, loaded, c)
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := dotenv.Write(&buf, &struct {
+			Items []string `env:"ITEMS"`
+		}{Items: []string{"a,b"}})
+		tt.AssertErrContains(t, err, "ITEMS", "item 0", "cannot contain commas", "a,b")
+
+		err = dotenv.Write(&buf, &struct {
+			Items map[string]int `env:"ITEMS"`
+		}{Items: map[string]int{"a": 1}})
+		tt.AssertErrContains(t, err, "ITEMS", "cannot convert")
+
+		err = dotenv.Write(&buf, 42)
+		tt.AssertErrContains(t, err, "expected struct pointer")
+	})
+}
@@ -0,0 +1,309 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ParseOptions allows the user to customize the behavior of Parse() and Load()
+type ParseOptions struct {
+	// LookupEnv is used for expanding `${VAR}` references to variables
+	// that were not defined before on the same file, it is os.LookupEnv
+	// by default. Undefined variables are expanded to empty strings.
+	LookupEnv func(name string) (string, bool)
+}
+
+// Parse reads the variables of a .env file, e.g.:
+//
+//	# Comments and blank lines are ignored
+//	export HOST=localhost     # The `export` prefix is optional
+//	URL="http://${HOST}:8080" # Variables are expanded inside double quotes
+//	GREETING='Hello ${NAME}'  # but not inside single quotes
+//	CERT="-----BEGIN CERTIFICATE-----
+//	...
+//	-----END CERTIFICATE-----"
+//
+// Double quoted values support the escape sequences \n, \r, \t, \", \\ and \$,
+// single quoted values are kept as they are and unquoted values end at the
+// end of the line or at a `#` preceded by a space.
+func Parse(r io.Reader, opts ...ParseOptions) (map[string]string, error) {
+	var o ParseOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if o.LookupEnv == nil {
+		o.LookupEnv = os.LookupEnv
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading .env input: %w", err)
+	}
+
+	p := parser{
+		src:       strings.ReplaceAll(string(b), "\r\n", "\n"),
+		line:      1,
+		vars:      map[string]string{},
+		lookupEnv: o.LookupEnv,
+	}
+
+	for {
+		p.skipBlankLinesAndComments()
+		if p.eof() {
+			break
+		}
+
+		startLine := p.line
+		err := p.parseAssignment()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing .env input on line %d: %w", startLine, err)
+		}
+	}
+
+	return p.vars, nil
+}
+
+type parser struct {
+	src  string
+	pos  int
+	line int
+
+	vars      map[string]string
+	lookupEnv func(name string) (string, bool)
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *parser) skipSpaces() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.next()
+	}
+}
+
+func (p *parser) skipLine() {
+	for !p.eof() && p.next() != '\n' {
+	}
+}
+
+func (p *parser) skipBlankLinesAndComments() {
+	for !p.eof() {
+		p.skipSpaces()
+		switch p.peek() {
+		case '\n':
+			p.next()
+		case '#':
+			p.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) parseAssignment() error {
+	if strings.HasPrefix(p.src[p.pos:], "export ") || strings.HasPrefix(p.src[p.pos:], "export\t") {
+		p.pos += len("export")
+		p.skipSpaces()
+	}
+
+	start := p.pos
+	for !p.eof() && isNameChar(p.peek()) {
+		p.next()
+	}
+	name := p.src[start:p.pos]
+	if name == "" {
+		return fmt.Errorf("expected variable name but got: %q", p.peek())
+	}
+
+	p.skipSpaces()
+	if p.peek() != '=' {
+		return fmt.Errorf("expected '=' after variable name %s", name)
+	}
+	p.next()
+	p.skipSpaces()
+
+	var value string
+	var err error
+	switch p.peek() {
+	case '"':
+		value, err = p.parseDoubleQuoted()
+	case '\'':
+		value, err = p.parseSingleQuoted()
+	default:
+		value, err = p.parseUnquoted()
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+
+	p.vars[name] = value
+	return nil
+}
+
+func (p *parser) parseDoubleQuoted() (string, error) {
+	p.next()
+
+	var value strings.Builder
+	for {
+		if p.eof() {
+			return "", fmt.Errorf("missing closing double quote")
+		}
+
+		c := p.next()
+		switch {
+		case c == '"':
+			return value.String(), p.endQuotedValue()
+
+		case c == '\\' && !p.eof():
+			escaped := p.next()
+			switch escaped {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '"', '\\', '$':
+				value.WriteByte(escaped)
+			default:
+				// Unknown escape sequences are kept as they are:
+				value.WriteByte('\\')
+				value.WriteByte(escaped)
+			}
+
+		case c == '$' && p.peek() == '{':
+			expanded, end, err := p.expandVar(p.src, p.pos-1)
+			if err != nil {
+				return "", err
+			}
+			value.WriteString(expanded)
+			p.pos = end
+
+		default:
+			value.WriteByte(c)
+		}
+	}
+}
+
+func (p *parser) parseSingleQuoted() (string, error) {
+	p.next()
+
+	start := p.pos
+	for !p.eof() && p.peek() != '\'' {
+		p.next()
+	}
+	if p.eof() {
+		return "", fmt.Errorf("missing closing single quote")
+	}
+
+	value := p.src[start:p.pos]
+	p.next()
+	return value, p.endQuotedValue()
+}
+
+// endQuotedValue makes sure there is nothing but
+// spaces and comments after the closing quote.
+func (p *parser) endQuotedValue() error {
+	p.skipSpaces()
+	switch p.peek() {
+	case '#':
+		p.skipLine()
+	case '\n':
+		p.next()
+	case 0:
+	default:
+		return fmt.Errorf("unexpected character after closing quote: %q", p.peek())
+	}
+	return nil
+}
+
+func (p *parser) parseUnquoted() (string, error) {
+	start := p.pos
+	end := p.pos
+	for !p.eof() && p.peek() != '\n' {
+		c := p.next()
+		if c == '#' && (p.pos-1 == start || isSpace(p.src[p.pos-2])) {
+			p.skipLine()
+			break
+		}
+		end = p.pos
+	}
+	if p.peek() == '\n' {
+		p.next()
+	}
+
+	raw := strings.TrimSpace(p.src[start:end])
+
+	var value strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '$' && i+1 < len(raw) && raw[i+1] == '{' {
+			expanded, end, err := p.expandVar(raw, i)
+			if err != nil {
+				return "", err
+			}
+			value.WriteString(expanded)
+			i = end - 1
+			continue
+		}
+		value.WriteByte(raw[i])
+	}
+
+	return value.String(), nil
+}
+
+// expandVar expands the `${VAR}` reference starting at
+// s[start] and returns the position right after it.
+func (p *parser) expandVar(s string, start int) (value string, end int, err error) {
+	closing := strings.IndexAny(s[start:], "}\n")
+	if closing == -1 || s[start+closing] != '}' {
+		return "", 0, fmt.Errorf("missing closing brace for variable reference")
+	}
+
+	name := s[start+2 : start+closing]
+	if name == "" {
+		return "", 0, fmt.Errorf("empty variable reference")
+	}
+	for i := 0; i < len(name); i++ {
+		if !isNameChar(name[i]) {
+			return "", 0, fmt.Errorf("invalid variable reference: ${%s}", name)
+		}
+	}
+
+	value, found := p.vars[name]
+	if !found {
+		value, _ = p.lookupEnv(name)
+	}
+
+	return value, start + closing + 1, nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '.' ||
+		'a' <= c && c <= 'z' ||
+		'A' <= c && c <= 'Z' ||
+		'0' <= c && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
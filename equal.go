@@ -0,0 +1,245 @@
+package structi
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// UnexportedPolicy tells the Equal() function what to do with unexported fields
+type UnexportedPolicy int
+
+// These are the available policies for unexported fields
+const (
+	IgnoreUnexported UnexportedPolicy = iota
+	CompareUnexported
+)
+
+// EqualOptions allows the user to customize the behavior of Equal()
+type EqualOptions struct {
+	// FloatTolerance is the maximum absolute difference
+	// for two floats to still be considered equal.
+	FloatTolerance float64
+
+	// NilEqualsEmpty makes nil slices and maps
+	// equal to empty slices and maps.
+	NilEqualsEmpty bool
+
+	// Unexported is IgnoreUnexported by default
+	Unexported UnexportedPolicy
+}
+
+// Equal compares two values recursively similarly to reflect.DeepEqual,
+// but it ignores the fields tagged with `cmp:"-"` and can be customized
+// with the EqualOptions.
+//
+// If the values are different the path to the first mismatching
+// value is also returned, e.g. `Address.Lines[1]`.
+//
+// Types that have an `Equal(T) bool` method, like time.Time,
+// are compared using that method.
+func Equal(a any, b any, opts ...EqualOptions) (equal bool, mismatchPath string) {
+	var o EqualOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	va := reflect.ValueOf(a)
+	vb := reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() {
+		return !va.IsValid() && !vb.IsValid(), ""
+	}
+
+	if va.Type() != vb.Type() {
+		return false, ""
+	}
+
+	c := comparer{
+		opts:    o,
+		visited: map[visitedPair]bool{},
+	}
+	return c.equal("", va, vb)
+}
+
+type comparer struct {
+	opts EqualOptions
+
+	// visited keeps track of the pairs of pointers, maps and slices
+	// already compared so that cyclic values don't cause infinite loops.
+	visited map[visitedPair]bool
+}
+
+// visitedPair identifies a pair of pointers, maps or slices, the type
+// and length are needed because different slices may share the same
+// data pointer, e.g. `s` and `s[:1]`, just like on reflect.DeepEqual.
+type visitedPair struct {
+	a   uintptr
+	b   uintptr
+	t   reflect.Type
+	len int
+}
+
+// isVisited reports whether the pair of values was already compared,
+// and marks it as visited otherwise.
+func (c comparer) isVisited(a reflect.Value, b reflect.Value) bool {
+	// The lengths of slices are checked before calling this function,
+	// so values pointing to the same data are always equal:
+	if a.Pointer() == b.Pointer() {
+		return true
+	}
+
+	key := visitedPair{a: a.Pointer(), b: b.Pointer(), t: a.Type()}
+	if a.Kind() == reflect.Slice {
+		key.len = a.Len()
+	}
+
+	if c.visited[key] {
+		return true
+	}
+	c.visited[key] = true
+	return false
+}
+
+var boolType = reflect.TypeOf(true)
+
+func (c comparer) equal(path string, a reflect.Value, b reflect.Value) (bool, string) {
+	if a.CanInterface() {
+		method, found := a.Type().MethodByName("Equal")
+		if found &&
+			method.Type.NumIn() == 2 && method.Type.In(1) == a.Type() &&
+			method.Type.NumOut() == 1 && method.Type.Out(0) == boolType &&
+			!(a.Kind() == reflect.Ptr && (a.IsNil() || b.IsNil())) {
+
+			isEqual := a.Method(method.Index).Call([]reflect.Value{b})[0].Bool()
+			return isEqual, path
+		}
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil(), path
+		}
+
+		if c.isVisited(a, b) {
+			return true, ""
+		}
+
+		return c.equal(path, a.Elem(), b.Elem())
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil(), path
+		}
+
+		if a.Elem().Type() != b.Elem().Type() {
+			return false, path
+		}
+
+		return c.equal(path, a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		return c.equalStruct(path, a, b)
+
+	case reflect.Slice, reflect.Map:
+		if a.IsNil() != b.IsNil() && !c.opts.NilEqualsEmpty {
+			return false, path
+		}
+
+		if a.Len() != b.Len() {
+			return false, path
+		}
+
+		if a.Len() == 0 || c.isVisited(a, b) {
+			return true, ""
+		}
+
+		if a.Kind() == reflect.Slice {
+			return c.equalItems(path, a, b)
+		}
+
+		for _, key := range sortedMapKeys(a) {
+			itemPath := fmt.Sprintf("%s[%v]", path, key)
+			bItem := b.MapIndex(key)
+			if !bItem.IsValid() {
+				return false, itemPath
+			}
+
+			isEqual, mismatchPath := c.equal(itemPath, a.MapIndex(key), bItem)
+			if !isEqual {
+				return false, mismatchPath
+			}
+		}
+		return true, ""
+
+	case reflect.Array:
+		return c.equalItems(path, a, b)
+
+	case reflect.Float32, reflect.Float64:
+		return c.equalFloats(a.Float(), b.Float()), path
+
+	case reflect.Complex64, reflect.Complex128:
+		ca, cb := a.Complex(), b.Complex()
+		return c.equalFloats(real(ca), real(cb)) && c.equalFloats(imag(ca), imag(cb)), path
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int(), path
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint(), path
+
+	case reflect.String:
+		return a.String() == b.String(), path
+
+	case reflect.Bool:
+		return a.Bool() == b.Bool(), path
+
+	case reflect.Func:
+		// Just like reflect.DeepEqual funcs are only equal if both are nil:
+		return a.IsNil() && b.IsNil(), path
+
+	case reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer(), path
+	}
+
+	return false, path
+}
+
+func (c comparer) equalStruct(path string, a reflect.Value, b reflect.Value) (bool, string) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && c.opts.Unexported == IgnoreUnexported {
+			continue
+		}
+
+		// Note that we can't use the cached fieldInfo here
+		// because it doesn't include the unexported fields:
+		if field.Tag.Get("cmp") == "-" {
+			continue
+		}
+
+		fieldPath := joinPath(path, field.Name)
+		isEqual, mismatchPath := c.equal(fieldPath, a.Field(i), b.Field(i))
+		if !isEqual {
+			return false, mismatchPath
+		}
+	}
+
+	return true, ""
+}
+
+func (c comparer) equalItems(path string, a reflect.Value, b reflect.Value) (bool, string) {
+	for i := 0; i < a.Len(); i++ {
+		isEqual, mismatchPath := c.equal(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i))
+		if !isEqual {
+			return false, mismatchPath
+		}
+	}
+
+	return true, ""
+}
+
+func (c comparer) equalFloats(a float64, b float64) bool {
+	return a == b || math.Abs(a-b) <= c.opts.FloatTolerance
+}
@@ -0,0 +1,242 @@
+package structi_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func TestEqual(t *testing.T) {
+	type Address struct {
+		City  string
+		Lines []string
+	}
+
+	type User struct {
+		ID        int `cmp:"-"`
+		Name      string
+		Score     float64
+		Address   *Address
+		Labels    map[string]string
+		UpdatedAt time.Time `cmp:"-"`
+		CreatedAt time.Time
+		Any       any
+
+		internal int
+	}
+
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should compare values recursively", func(t *testing.T) {
+		u1 := User{
+			Name:      "fakeName",
+			Score:     4.2,
+			Address:   &Address{City: "fakeCity", Lines: []string{"l1"}},
+			Labels:    map[string]string{"a": "1"},
+			CreatedAt: createdAt,
+			Any:       []int{1, 2},
+		}
+		u2 := u1
+		u2.Address = &Address{City: "fakeCity", Lines: []string{"l1"}}
+		u2.Labels = map[string]string{"a": "1"}
+		u2.Any = []int{1, 2}
+
+		equal, path := structi.Equal(u1, u2)
+		tt.AssertEqual(t, equal, true)
+		tt.AssertEqual(t, path, "")
+	})
+
+	t.Run("should return the path of the first mismatch", func(t *testing.T) {
+		tests := []struct {
+			desc         string
+			a            User
+			b            User
+			expectedPath string
+		}{
+			{
+				desc:         "simple field",
+				a:            User{Name: "a"},
+				b:            User{Name: "b"},
+				expectedPath: "Name",
+			},
+			{
+				desc:         "nested slice item",
+				a:            User{Address: &Address{Lines: []string{"l1", "l2"}}},
+				b:            User{Address: &Address{Lines: []string{"l1", "l3"}}},
+				expectedPath: "Address.Lines[1]",
+			},
+			{
+				desc:         "nil vs non-nil pointer",
+				a:            User{},
+				b:            User{Address: &Address{}},
+				expectedPath: "Address",
+			},
+			{
+				desc:         "map item",
+				a:            User{Labels: map[string]string{"a": "1"}},
+				b:            User{Labels: map[string]string{"a": "2"}},
+				expectedPath: "Labels[a]",
+			},
+			{
+				desc:         "types with an Equal method",
+				a:            User{CreatedAt: createdAt},
+				b:            User{CreatedAt: createdAt.Add(time.Second)},
+				expectedPath: "CreatedAt",
+			},
+			{
+				desc:         "interfaces with different dynamic types",
+				a:            User{Any: 1},
+				b:            User{Any: 1.0},
+				expectedPath: "Any",
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.desc, func(t *testing.T) {
+				equal, path := structi.Equal(test.a, test.b)
+				tt.AssertEqual(t, equal, false)
+				tt.AssertEqual(t, path, test.expectedPath)
+			})
+		}
+	})
+
+	t.Run("should ignore fields tagged with cmp:\"-\"", func(t *testing.T) {
+		equal, _ := structi.Equal(
+			User{ID: 1, UpdatedAt: createdAt},
+			User{ID: 2, UpdatedAt: time.Now()},
+		)
+		tt.AssertEqual(t, equal, true)
+	})
+
+	t.Run("should compare times using their Equal method", func(t *testing.T) {
+		equal, _ := structi.Equal(
+			User{CreatedAt: createdAt},
+			User{CreatedAt: createdAt.In(time.FixedZone("other", 3600))},
+		)
+		tt.AssertEqual(t, equal, true)
+	})
+
+	t.Run("should accept a float tolerance", func(t *testing.T) {
+		equal, path := structi.Equal(User{Score: 1.0}, User{Score: 1.0001})
+		tt.AssertEqual(t, equal, false)
+		tt.AssertEqual(t, path, "Score")
+
+		equal, _ = structi.Equal(User{Score: 1.0}, User{Score: 1.0001}, structi.EqualOptions{
+			FloatTolerance: 0.001,
+		})
+		tt.AssertEqual(t, equal, true)
+	})
+
+	t.Run("should optionally consider nil and empty slices and maps equal", func(t *testing.T) {
+		a := User{Address: &Address{}}
+		b := User{Address: &Address{Lines: []string{}}, Labels: map[string]string{}}
+
+		equal, path := structi.Equal(a, b)
+		tt.AssertEqual(t, equal, false)
+		tt.AssertEqual(t, path, "Address.Lines")
+
+		equal, _ = structi.Equal(a, b, structi.EqualOptions{
+			NilEqualsEmpty: true,
+		})
+		tt.AssertEqual(t, equal, true)
+	})
+
+	t.Run("should optionally compare unexported fields", func(t *testing.T) {
+		equal, _ := structi.Equal(User{internal: 1}, User{internal: 2})
+		tt.AssertEqual(t, equal, true)
+
+		equal, path := structi.Equal(User{internal: 1}, User{internal: 2}, structi.EqualOptions{
+			Unexported: structi.CompareUnexported,
+		})
+		tt.AssertEqual(t, equal, false)
+		tt.AssertEqual(t, path, "internal")
+	})
+
+	t.Run("should compare unexported maps, slices and times", func(t *testing.T) {
+		type Internal struct {
+			labels  map[string]int
+			items   []map[string]int
+			created time.Time
+		}
+
+		now := time.Now()
+		a := Internal{
+			labels:  map[string]int{"a": 1, "b": 2},
+			items:   []map[string]int{{"c": 3}},
+			created: now,
+		}
+		b := Internal{
+			labels:  map[string]int{"a": 1, "b": 2},
+			items:   []map[string]int{{"c": 3}},
+			created: now,
+		}
+
+		opts := structi.EqualOptions{Unexported: structi.CompareUnexported}
+		equal, _ := structi.Equal(a, b, opts)
+		tt.AssertEqual(t, equal, true)
+
+		b.labels["b"] = 3
+		equal, path := structi.Equal(a, b, opts)
+		tt.AssertEqual(t, equal, false)
+		tt.AssertEqual(t, path, "labels[b]")
+
+		b.labels = a.labels
+		b.items[0] = map[string]int{"d": 3}
+		equal, path = structi.Equal(a, b, opts)
+		tt.AssertEqual(t, equal, false)
+		tt.AssertEqual(t, path, "items[0][c]")
+
+		b.items = a.items
+		b.created = now.Add(time.Second)
+		equal, path = structi.Equal(a, b, opts)
+		tt.AssertEqual(t, equal, false)
+		tt.AssertEqual(t, strings.HasPrefix(path, "created"), true)
+	})
+
+	t.Run("should not loop forever on cyclic values", func(t *testing.T) {
+		type Node struct {
+			Value int
+			Next  *Node
+		}
+
+		a := &Node{Value: 1}
+		a.Next = a
+		b := &Node{Value: 1}
+		b.Next = b
+
+		equal, _ := structi.Equal(a, b)
+		tt.AssertEqual(t, equal, true)
+
+		type Container struct {
+			Map   map[string]any
+			Slice []any
+		}
+
+		m1 := map[string]any{"value": 1}
+		m1["self"] = m1
+		m2 := map[string]any{"value": 1}
+		m2["self"] = m2
+		s1 := []any{1, nil}
+		s1[1] = s1
+		s2 := []any{1, nil}
+		s2[1] = s2
+
+		equal, _ = structi.Equal(Container{Map: m1, Slice: s1}, Container{Map: m2, Slice: s2})
+		tt.AssertEqual(t, equal, true)
+
+		m2["value"] = 2
+		equal, path := structi.Equal(Container{Map: m1}, Container{Map: m2})
+		tt.AssertEqual(t, equal, false)
+		tt.AssertEqual(t, path, "Map[value]")
+	})
+
+	t.Run("should not consider values of different types equal", func(t *testing.T) {
+		equal, _ := structi.Equal(User{}, Address{})
+		tt.AssertEqual(t, equal, false)
+
+		equal, _ = structi.Equal(nil, nil)
+		tt.AssertEqual(t, equal, true)
+	})
+}
@@ -38,6 +38,9 @@ func main() {
 			Country string `map:"country"`
 		} `map:"address"`
 		SomeSlice []int `map:"some_slice"`
+		Phones    []struct {
+			Number string `map:"number"`
+		} `map:"phones"`
 	}
 
 	err := LoadFromMap(&user, map[string]any{
@@ -52,6 +55,11 @@ func main() {
 		// differs from the struct slice it will convert all
 		// values correctly:
 		"some_slice": []float64{1.0, 2.0, 3.0},
+		// Slices of maps are also converted into slices of structs
+		// by matching the map keys with the `map` tags:
+		"phones": []map[string]any{
+			{"number": "fakeNumber"},
+		},
 	})
 	if err != nil {
 		log.Fatalf("error loading data from map: %v", err)
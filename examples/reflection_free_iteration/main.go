@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/vingarcia/structi"
+)
+
+//go:generate go run ../../cmd/structi-gen -type Config
+
+// Config has a reflection-free iterator generated on the
+// main_structi.go file, which is used automatically by ForEach()
+type Config struct {
+	Home    string        `env:"HOME"`
+	Shell   string        `env:"SHELL"`
+	Timeout time.Duration `env:"TIMEOUT"`
+}
+
+func main() {
+	var config Config
+	err := structi.ForEach(&config, func(field structi.Field) error {
+		envTag := field.Tags["env"]
+		if envTag == "" {
+			return nil
+		}
+
+		if field.Type == reflect.TypeOf(time.Duration(0)) {
+			return field.Set(time.Second)
+		}
+
+		return field.Set(os.Getenv(envTag))
+	})
+	if err != nil {
+		log.Fatalf("error loading env vars: %v", err)
+	}
+
+	b, _ := json.MarshalIndent(config, "", "  ")
+	fmt.Println("loaded config:", string(b))
+}
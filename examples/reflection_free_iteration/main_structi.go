@@ -0,0 +1,43 @@
+// Code generated by structi-gen. DO NOT EDIT.
+
+package main
+
+import (
+	"time"
+
+	"github.com/vingarcia/structi"
+)
+
+func init() {
+	structi.RegisterGeneratedIterator((*Config)(nil), func(structPtr any, yield func(idx int, valuePtr any, trySet func(value any) bool) error) error {
+		s := structPtr.(*Config)
+		if err := yield(0, &s.Home, func(value any) bool {
+			v, ok := value.(string)
+			if ok {
+				s.Home = v
+			}
+			return ok
+		}); err != nil {
+			return err
+		}
+		if err := yield(1, &s.Shell, func(value any) bool {
+			v, ok := value.(string)
+			if ok {
+				s.Shell = v
+			}
+			return ok
+		}); err != nil {
+			return err
+		}
+		if err := yield(2, &s.Timeout, func(value any) bool {
+			v, ok := value.(time.Duration)
+			if ok {
+				s.Timeout = v
+			}
+			return ok
+		}); err != nil {
+			return err
+		}
+		return nil
+	})
+}
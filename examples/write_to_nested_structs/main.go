@@ -2,7 +2,6 @@ package main
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"reflect"
@@ -16,17 +15,21 @@ func main() {
 		OtherStruct *struct {
 			Attr2 int `env:"attr2"`
 		}
+		EmptyStruct *struct {
+			Attr3 int `env:"attr3"`
+		}
 	}
 	err := structi.ForEach(&output, func(field structi.Field) error {
 		if field.Kind == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
-			subStruct := reflect.New(field.Type.Elem())
+			// The nil pointer is only allocated if one of the
+			// fields of the nested struct is set:
+			return field.ForEachNested(func(field structi.Field) error {
+				if field.Name == "Attr3" {
+					return nil
+				}
 
-			return errors.Join(
-				structi.ForEach(subStruct, func(field structi.Field) error {
-					return field.Set(42)
-				}),
-				field.Set(subStruct),
-			)
+				return field.Set(42)
+			})
 		}
 
 		return field.Set(64)
@@ -0,0 +1,379 @@
+package structi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vingarcia/structi/internal/types"
+	"github.com/vingarcia/structi/tags"
+)
+
+// FlattenOptions allows the user to customize
+// the behavior of Flatten() and Unflatten()
+type FlattenOptions struct {
+	// Tag is used for reading the key of each field, e.g. "json",
+	// if empty or if the field has no such tag the field name is used.
+	//
+	// Fields tagged with "-" are ignored.
+	Tag string
+}
+
+// Flatten converts the input struct (or pointer to struct) into a flat map
+// whose keys are the paths to each value joined by `sep`, e.g. with sep "."
+// the field `MaxConns` of the struct `Pool` on the field `DB` has the key
+// "DB.Pool.MaxConns" and the items of slices and arrays have keys like
+// "Items.0.Name".
+//
+// Nil pointers, nil interfaces and empty slices and maps have no keys
+// on the output, structs without exported fields, e.g. time.Time,
+// and byte slices are kept as single values.
+func Flatten(targetStruct any, sep string, opts ...FlattenOptions) (map[string]any, error) {
+	var o FlattenOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	f := flattener{
+		opts:     o,
+		sep:      sep,
+		output:   map[string]any{},
+		visiting: map[visitKey]bool{},
+	}
+
+	v := reflect.ValueOf(targetStruct)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		f.visiting[visitKey{ptr: v.Pointer(), t: v.Type()}] = true
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can only flatten structs, but got: %T", targetStruct)
+	}
+
+	err := f.flattenStruct(v, "")
+	return f.output, err
+}
+
+type flattener struct {
+	opts     FlattenOptions
+	sep      string
+	output   map[string]any
+	visiting map[visitKey]bool
+}
+
+func (f flattener) joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + f.sep + key
+}
+
+func (f flattener) flattenStruct(v reflect.Value, prefix string) error {
+	_, fields, err := getStructInfoForType(reflect.PointerTo(v.Type()))
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		key, tagName := fieldKey(field, f.opts.Tag)
+		if tagName == "-" {
+			continue
+		}
+
+		fieldValue := v.Field(field.idx)
+		if elem, ok := promotedStruct(field, tagName, fieldValue); ok {
+			err := f.flattenStruct(elem, prefix)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := f.flattenValue(fieldValue, f.joinKey(prefix, key))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f flattener) flattenValue(v reflect.Value, key string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		ptrKey := visitKey{ptr: v.Pointer(), t: v.Type()}
+		if f.visiting[ptrKey] {
+			return fmt.Errorf("%w on '%s': %v already being visited", ErrCycle, key, v.Type())
+		}
+		f.visiting[ptrKey] = true
+		defer delete(f.visiting, ptrKey)
+
+		return f.flattenValue(v.Elem(), key)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return f.flattenValue(v.Elem(), key)
+
+	case reflect.Struct:
+		if isOpaqueStruct(v.Type()) {
+			f.output[key] = v.Interface()
+			return nil
+		}
+		return f.flattenStruct(v, key)
+
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			f.output[key] = v.Interface()
+			return nil
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			err := f.flattenValue(v.Index(i), f.joinKey(key, strconv.Itoa(i)))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		for _, mapKey := range sortedMapKeys(v) {
+			err := f.flattenValue(v.MapIndex(mapKey), f.joinKey(key, fmt.Sprint(mapKey)))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return fmt.Errorf("cannot flatten '%s' of type %v", key, v.Type())
+	}
+
+	f.output[key] = v.Interface()
+	return nil
+}
+
+// Unflatten is the inverse of Flatten(), it fills the target struct with
+// the values of a flat map whose keys are paths joined by `sep`, e.g.
+// "DB.Pool.MaxConns" or "Items.0.Name".
+//
+// Field names are matched case-insensitively if no field has the exact
+// key, nil pointers and maps are allocated as needed and slices grow
+// to fit the indexes found on the keys. String values are parsed into
+// the type of the target field, so it works with the values read from
+// env files or key-value stores.
+func Unflatten(flatMap map[string]any, targetStruct any, sep string, opts ...FlattenOptions) error {
+	var o FlattenOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	_, v, _, err := getStructInfo(targetStruct)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(flatMap))
+	for key := range flatMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		err := unflattenValue(v.Elem(), strings.Split(key, sep), flatMap[key], "", o)
+		if err != nil {
+			return fmt.Errorf("error unflattening key '%s': %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// unflattenValue sets the value on the path described by the segments,
+// `v` must be addressable unless it is a map.
+func unflattenValue(v reflect.Value, segments []string, value any, layout string, opts FlattenOptions) error {
+	if len(segments) == 0 {
+		convertedValue, err := convertFlatValue(value, v.Type(), layout)
+		if err != nil {
+			return err
+		}
+		v.Set(convertedValue)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return unflattenValue(v.Elem(), segments, value, layout, opts)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			if !anyMapType.AssignableTo(v.Type()) {
+				return fmt.Errorf("cannot set '%s' on nil %v", segments[0], v.Type())
+			}
+			v.Set(reflect.MakeMap(anyMapType))
+		}
+
+		elem := v.Elem()
+		if elem.Kind() != reflect.Map {
+			return fmt.Errorf("cannot set '%s' on value of type %v", segments[0], elem.Type())
+		}
+		return unflattenValue(elem, segments, value, layout, opts)
+
+	case reflect.Struct:
+		field, fieldValue, found := lookupFlatField(v, segments[0], opts.Tag)
+		if !found {
+			return fmt.Errorf("no field matches '%s' on type %v", segments[0], v.Type())
+		}
+		return unflattenValue(fieldValue, segments[1:], value, field.Tags["layout"], opts)
+
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(segments[0])
+		if err != nil || index < 0 {
+			return fmt.Errorf("invalid index '%s' for %v", segments[0], v.Type())
+		}
+
+		if index >= v.Len() {
+			if v.Kind() == reflect.Array {
+				return fmt.Errorf("index %d out of range for %v", index, v.Type())
+			}
+			zeroItems := reflect.MakeSlice(v.Type(), index+1-v.Len(), index+1-v.Len())
+			v.Set(reflect.AppendSlice(v, zeroItems))
+		}
+		return unflattenValue(v.Index(index), segments[1:], value, layout, opts)
+
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+
+		mapKey, err := convertFlatValue(segments[0], v.Type().Key(), "")
+		if err != nil {
+			return fmt.Errorf("invalid key '%s' for %v: %w", segments[0], v.Type(), err)
+		}
+
+		// Map items are not addressable, so we update a copy and store it back:
+		item := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(mapKey); existing.IsValid() {
+			item.Set(existing)
+		}
+
+		err = unflattenValue(item, segments[1:], value, layout, opts)
+		if err != nil {
+			return err
+		}
+		v.SetMapIndex(mapKey, item)
+		return nil
+	}
+
+	return fmt.Errorf("cannot set '%s' on value of type %v", segments[0], v.Type())
+}
+
+// lookupFlatField finds the field whose key matches the name, first
+// by exact match, then case-insensitively and then on the promoted
+// fields of embedded structs.
+func lookupFlatField(v reflect.Value, name string, tag string) (fieldInfo, reflect.Value, bool) {
+	_, fields, err := getStructInfoForType(reflect.PointerTo(v.Type()))
+	if err != nil {
+		return fieldInfo{}, reflect.Value{}, false
+	}
+
+	for _, match := range []func(key string) bool{
+		func(key string) bool { return key == name },
+		func(key string) bool { return strings.EqualFold(key, name) },
+	} {
+		for _, field := range fields {
+			key, tagName := fieldKey(field, tag)
+			if tagName == "-" || (field.IsEmbeded && tagName == "") {
+				continue
+			}
+			if match(key) {
+				return field, v.Field(field.idx), true
+			}
+		}
+	}
+
+	for _, field := range fields {
+		_, tagName := fieldKey(field, tag)
+		if !field.IsEmbeded || tagName != "" {
+			continue
+		}
+
+		fieldValue := v.Field(field.idx)
+		structType := field.Type
+		if structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+		if structType.Kind() != reflect.Struct {
+			continue
+		}
+
+		// Nil embedded pointers are only allocated if the field is found:
+		embedded := reflect.New(structType)
+		if fieldValue.Kind() != reflect.Ptr {
+			embedded = fieldValue.Addr()
+		} else if !fieldValue.IsNil() {
+			embedded = fieldValue
+		}
+
+		promotedField, promotedValue, found := lookupFlatField(embedded.Elem(), name, tag)
+		if !found {
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
+			fieldValue.Set(embedded)
+		}
+		return promotedField, promotedValue, true
+	}
+
+	return fieldInfo{}, reflect.Value{}, false
+}
+
+// fieldKey returns the name read from the tag if there is one or the field name
+// otherwise, and also the name read from the tag which might be "-".
+func fieldKey(field fieldInfo, tag string) (key string, tagName string) {
+	if tag != "" {
+		tagName, _ = tags.SplitOptions(field.Tags[tag])
+	}
+
+	if tagName != "" {
+		return tagName, tagName
+	}
+	return field.Name, tagName
+}
+
+var (
+	anyMapType   = reflect.TypeOf(map[string]any{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// convertFlatValue works like the conversion of Field.Set() but
+// also parses strings into numbers and booleans.
+func convertFlatValue(value any, t reflect.Type, layout string) (reflect.Value, error) {
+	elemType := t
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	if str, ok := value.(string); ok && elemType.Kind() != reflect.String && elemType != durationType {
+		parsedValue, err := types.StringToType(elemType, str)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot parse '%s' as %v: %w", str, t, err)
+		}
+		value = parsedValue.Interface()
+	}
+
+	return types.NewConverter(value).WithTimeLayout(layout).Convert(t)
+}
@@ -0,0 +1,210 @@
+package structi_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+type flatPool struct {
+	MaxConns int           `env:"max_conns"`
+	Timeout  time.Duration `env:"timeout"`
+}
+
+type flatDB struct {
+	Host string    `env:"host"`
+	Pool *flatPool `env:"pool"`
+}
+
+type flatItem struct {
+	Name  string  `env:"name"`
+	Price float64 `env:"price"`
+}
+
+type flatConfig struct {
+	Name      string            `env:"name"`
+	DB        flatDB            `env:"db"`
+	Items     []flatItem        `env:"items"`
+	Labels    map[string]string `env:"labels"`
+	Ports     [2]int            `env:"ports"`
+	StartedAt time.Time         `env:"started_at" layout:"2006-01-02"`
+	Debug     *bool             `env:"debug"`
+	Internal  string            `env:"-"`
+}
+
+func TestFlatten(t *testing.T) {
+	t.Run("should produce keys for nested values", func(t *testing.T) {
+		input := flatConfig{
+			Name: "app",
+			DB: flatDB{
+				Host: "localhost",
+				Pool: &flatPool{MaxConns: 10, Timeout: 5 * time.Second},
+			},
+			Items:     []flatItem{{Name: "a", Price: 1.5}, {Name: "b"}},
+			Labels:    map[string]string{"env": "prod"},
+			Ports:     [2]int{80, 443},
+			StartedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Internal:  "foo",
+		}
+
+		output, err := structi.Flatten(&input, ".", structi.FlattenOptions{Tag: "env"})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, map[string]any{
+			"name":              "app",
+			"db.host":           "localhost",
+			"db.pool.max_conns": 10,
+			"db.pool.timeout":   5 * time.Second,
+			"items.0.name":      "a",
+			"items.0.price":     1.5,
+			"items.1.name":      "b",
+			"items.1.price":     0.0,
+			"labels.env":        "prod",
+			"ports.0":           80,
+			"ports.1":           443,
+			"started_at":        time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		})
+	})
+
+	t.Run("should use the field names and promote embedded fields", func(t *testing.T) {
+		type Base struct {
+			ID int
+		}
+		input := struct {
+			Base
+			Pool flatPool
+		}{
+			Base: Base{ID: 42},
+			Pool: flatPool{MaxConns: 1},
+		}
+
+		output, err := structi.Flatten(input, "__")
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, map[string]any{
+			"ID":             42,
+			"Pool__MaxConns": 1,
+			"Pool__Timeout":  time.Duration(0),
+		})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		_, err := structi.Flatten(42, ".")
+		tt.AssertErrContains(t, err, "can only flatten structs", "int")
+
+		root := &walkNode{Name: "root"}
+		root.Children = []*walkNode{{Parent: root}}
+		_, err = structi.Flatten(root, ".")
+		tt.AssertEqual(t, errors.Is(err, structi.ErrCycle), true)
+		tt.AssertErrContains(t, err, "Children.0.Parent", "walkNode")
+	})
+}
+
+func TestUnflatten(t *testing.T) {
+	t.Run("should be the inverse of Flatten", func(t *testing.T) {
+		debug := true
+		input := flatConfig{
+			Name: "app",
+			DB: flatDB{
+				Host: "localhost",
+				Pool: &flatPool{MaxConns: 10, Timeout: 5 * time.Second},
+			},
+			Items:     []flatItem{{Name: "a", Price: 1.5}, {Name: "b"}},
+			Labels:    map[string]string{"env": "prod"},
+			Ports:     [2]int{80, 443},
+			StartedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Debug:     &debug,
+		}
+
+		flat, err := structi.Flatten(input, "/", structi.FlattenOptions{Tag: "env"})
+		tt.AssertNoErr(t, err)
+
+		var output flatConfig
+		err = structi.Unflatten(flat, &output, "/", structi.FlattenOptions{Tag: "env"})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, input)
+	})
+
+	t.Run("should parse string values", func(t *testing.T) {
+		var output flatConfig
+		err := structi.Unflatten(map[string]any{
+			"name":              "app",
+			"db.pool.max_conns": "10",
+			"db.pool.timeout":   "1m",
+			"items.1.price":     "2.5",
+			"ports.1":           "443",
+			"started_at":        "2024-01-02",
+			"debug":             "true",
+		}, &output, ".", structi.FlattenOptions{Tag: "env"})
+		tt.AssertNoErr(t, err)
+
+		debug := true
+		tt.AssertEqual(t, output, flatConfig{
+			Name: "app",
+			DB: flatDB{
+				Pool: &flatPool{MaxConns: 10, Timeout: time.Minute},
+			},
+			Items:     []flatItem{{}, {Price: 2.5}},
+			Ports:     [2]int{0, 443},
+			StartedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Debug:     &debug,
+		})
+	})
+
+	t.Run("should match field names case-insensitively", func(t *testing.T) {
+		var output struct {
+			Pool   flatPool
+			Extras map[string]any
+		}
+		err := structi.Unflatten(map[string]any{
+			"pool_maxconns":   5,
+			"extras_a_b":      "foo",
+			"extras_a_c":      "bar",
+			"extras_toplevel": 1,
+		}, &output, "_")
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Pool.MaxConns, 5)
+		tt.AssertEqual(t, output.Extras, map[string]any{
+			"a":        map[string]any{"b": "foo", "c": "bar"},
+			"toplevel": 1,
+		})
+	})
+
+	t.Run("should fill the promoted fields of embedded structs", func(t *testing.T) {
+		type Base struct {
+			ID int
+		}
+		type Pool = flatPool
+		var output struct {
+			*Pool
+			*Base
+			Name string
+		}
+		err := structi.Unflatten(map[string]any{"ID": 42, "Name": "foo"}, &output, ".")
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Base, &Base{ID: 42})
+		tt.AssertEqual(t, output.Name, "foo")
+
+		// Embedded pointers are only allocated if they are used:
+		tt.AssertEqual(t, output.Pool == nil, true)
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		var output flatConfig
+		err := structi.Unflatten(map[string]any{"db.missing": 1}, &output, ".", structi.FlattenOptions{Tag: "env"})
+		tt.AssertErrContains(t, err, "db.missing", "no field matches 'missing'", "flatDB")
+
+		err = structi.Unflatten(map[string]any{"ports.2": 1}, &output, ".", structi.FlattenOptions{Tag: "env"})
+		tt.AssertErrContains(t, err, "ports.2", "index 2 out of range", "[2]int")
+
+		err = structi.Unflatten(map[string]any{"items.x.name": "a"}, &output, ".", structi.FlattenOptions{Tag: "env"})
+		tt.AssertErrContains(t, err, "items.x.name", "invalid index 'x'")
+
+		err = structi.Unflatten(map[string]any{"db.pool.max_conns": "ten"}, &output, ".", structi.FlattenOptions{Tag: "env"})
+		tt.AssertErrContains(t, err, "db.pool.max_conns", "cannot parse 'ten'", "int")
+
+		err = structi.Unflatten(map[string]any{}, output, ".")
+		tt.AssertErrContains(t, err, "expected struct pointer", "flatConfig")
+	})
+}
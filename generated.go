@@ -0,0 +1,84 @@
+package structi
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// GeneratedIterator is the signature of the iterators generated by
+// the `structi-gen` command, which iterate over the fields of a
+// specific struct type without using reflection.
+//
+// For each exported field it calls `yield` passing the index of the field
+// on the struct, a pointer to the field and a `trySet` function that sets
+// the field without reflection if the value has the exact type of the field,
+// returning false otherwise.
+type GeneratedIterator func(
+	structPtr any,
+	yield func(idx int, valuePtr any, trySet func(value any) bool) error,
+) error
+
+type generatedIterator struct {
+	iterate GeneratedIterator
+	fields  []fieldInfo
+
+	// positions maps the index of each field on the struct
+	// to the position of its info on the `fields` slice.
+	positions map[int]int
+}
+
+// This registry is kept as a pkg variable for
+// the same reasons as the structInfoCache.
+var generatedIterators = &sync.Map{}
+
+// RegisterGeneratedIterator makes ForEach() use the `iterator` function
+// for iterating over the type of `structPtr`, which should be a pointer
+// to a struct, e.g. `(*MyStruct)(nil)`.
+//
+// This function is meant to be called by the code generated by
+// the `structi-gen` command, and it panics if the input is invalid.
+func RegisterGeneratedIterator(structPtr any, iterator GeneratedIterator) {
+	_, fields, err := getStructInfoForType(reflect.TypeOf(structPtr))
+	if err != nil {
+		panic(fmt.Sprintf("structi: error registering generated iterator: %s", err))
+	}
+
+	positions := map[int]int{}
+	for i, field := range fields {
+		positions[field.idx] = i
+	}
+
+	generatedIterators.Store(reflect.TypeOf(structPtr), generatedIterator{
+		iterate:   iterator,
+		fields:    fields,
+		positions: positions,
+	})
+}
+
+func (g generatedIterator) forEach(v reflect.Value, iterate IteratorFunc) error {
+	return g.iterate(v.Interface(), func(idx int, valuePtr any, trySet func(value any) bool) error {
+		pos, found := g.positions[idx]
+		if !found {
+			return fmt.Errorf("generated iterator for type %v is out of date: no exported field with index %d", v.Type(), idx)
+		}
+		field := g.fields[pos]
+
+		set := setAttrValue(v, field)
+		err := iterate(Field{
+			fieldInfo: &field,
+			Value:     valuePtr,
+			Set: func(value any) error {
+				if trySet != nil && trySet(value) {
+					return nil
+				}
+				return set(value)
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("iteration error on field '%s' of type '%v': %w", field.Name, field.Type, err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package structi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+type generatedStruct struct {
+	Name    string `env:"NAME"`
+	private int
+	Port    int `env:"PORT"`
+	Tags    []string
+}
+
+func init() {
+	// This is equivalent to the code generated by `structi-gen`:
+	structi.RegisterGeneratedIterator((*generatedStruct)(nil), func(structPtr any, yield func(idx int, valuePtr any, trySet func(value any) bool) error) error {
+		s := structPtr.(*generatedStruct)
+		if err := yield(0, &s.Name, func(value any) bool {
+			v, ok := value.(string)
+			if ok {
+				s.Name = v + " (generated)"
+			}
+			return ok
+		}); err != nil {
+			return err
+		}
+		if err := yield(2, &s.Port, func(value any) bool {
+			v, ok := value.(int)
+			if ok {
+				s.Port = v
+			}
+			return ok
+		}); err != nil {
+			return err
+		}
+		if err := yield(3, &s.Tags, nil); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func TestGeneratedIterators(t *testing.T) {
+	t.Run("should dispatch to the generated iterator when available", func(t *testing.T) {
+		var s generatedStruct
+		fields := []string{}
+		err := structi.ForEach(&s, func(field structi.Field) error {
+			fields = append(fields, field.Name+":"+field.Tags["env"])
+
+			switch field.Name {
+			case "Name":
+				tt.AssertEqual(t, field.Value, &s.Name)
+				return field.Set("fakeName")
+			case "Port":
+				tt.AssertEqual(t, field.Kind, reflect.Int)
+				return field.Set(8080)
+			}
+			return field.Set([]any{"a", "b"})
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, fields, []string{"Name:NAME", "Port:PORT", "Tags:"})
+		tt.AssertEqual(t, s.private, 0)
+		tt.AssertEqual(t, s, generatedStruct{
+			Name: "fakeName (generated)",
+			Port: 8080,
+			Tags: []string{"a", "b"},
+		})
+	})
+
+	t.Run("should fallback to reflection if the value has a different type", func(t *testing.T) {
+		var s generatedStruct
+		err := structi.ForEach(&s, func(field structi.Field) error {
+			if field.Name == "Port" {
+				return field.Set(uint8(80))
+			}
+			return nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, s.Port, 80)
+	})
+
+	t.Run("should wrap errors from the iterator", func(t *testing.T) {
+		var s generatedStruct
+		err := structi.ForEach(&s, func(field structi.Field) error {
+			return field.Set(struct{}{})
+		})
+		tt.AssertErrContains(t, err, "iteration error", "Name", "string", "struct")
+	})
+
+	t.Run("should panic when registering invalid types", func(t *testing.T) {
+		payload := tt.PanicHandler(func() {
+			structi.RegisterGeneratedIterator(42, nil)
+		})
+		tt.AssertNotEqual(t, payload, nil)
+	})
+}
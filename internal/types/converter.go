@@ -3,6 +3,9 @@ package types
 import (
 	"fmt"
 	"reflect"
+	"strings"
+
+	"github.com/vingarcia/structi/tags"
 )
 
 // Converter was created to make it easier
@@ -12,11 +15,35 @@ import (
 // - type to *type
 // - *type to type
 // - type to type
+//
+// Besides the conversions allowed by reflect.Value.Convert()
+// it applies the following cross-kind rules:
+//
+// - numbers to strings are formatted as decimal numbers, e.g. 65 to "65"
+// - strings to and from []byte are converted as UTF-8 bytes
+// - strings to and from []rune are converted as unicode code points
+// - slices and arrays are converted item by item
+// - maps with string keys are converted to structs field by field
+// - maps to the registered implementations of interfaces, check RegisterUnionType()
+// - strings and numbers to and from time.Time, check WithTimeLayout()
+// - strings to and from time.Duration, e.g. "1h30m"
+//
+// And numeric conversions that would overflow or lose the sign or the
+// fractional part of the number return an error, unless WithLenientNumbers()
+// is used.
 type Converter struct {
 	BaseType  reflect.Type
 	BaseValue reflect.Value
 	ElemType  reflect.Type
 	ElemValue reflect.Value
+
+	// TimeLayout is used for converting strings and numbers
+	// to and from time.Time, check WithTimeLayout() for details.
+	TimeLayout string
+
+	// LenientNumbers allows lossy numeric conversions,
+	// check WithLenientNumbers() for details.
+	LenientNumbers bool
 }
 
 // NewConverter instantiates a Converter from
@@ -53,6 +80,31 @@ func NewConverter(v interface{}) Converter {
 	}
 }
 
+// WithTimeLayout returns a copy of the Converter that uses the given
+// layout for converting strings to and from time.Time values.
+//
+// The layouts "unix", "unixmilli", "unixmicro" and "unixnano" make it
+// parse and format the times as integers instead, if no layout is set
+// time.RFC3339 is used for strings and "unix" for numbers.
+func (p Converter) WithTimeLayout(layout string) Converter {
+	p.TimeLayout = layout
+	return p
+}
+
+// WithLenientNumbers returns a copy of the Converter that allows numeric
+// conversions that overflow, lose the sign or lose the fractional part
+// of the value, e.g. 300 to uint8 results in 44 and 3.9 to int in 3.
+func (p Converter) WithLenientNumbers(enabled bool) Converter {
+	p.LenientNumbers = enabled
+	return p
+}
+
+// nested instantiates a Converter for a nested value
+// keeping the configurations of the current Converter.
+func (p Converter) nested(v any) Converter {
+	return NewConverter(v).WithTimeLayout(p.TimeLayout).WithLenientNumbers(p.LenientNumbers)
+}
+
 // Convert attempts to convert the ElemValue to the destType received
 // as argument and then returns the converted reflect.Value or an error
 func (p Converter) Convert(destType reflect.Type) (reflect.Value, error) {
@@ -67,6 +119,17 @@ func (p Converter) Convert(destType reflect.Type) (reflect.Value, error) {
 		return reflect.New(destType).Elem(), nil
 	}
 
+	// Pointers whose methods are needed for implementing the target interface
+	// are stored as they are instead of being dereferenced:
+	if destType.Kind() == reflect.Interface &&
+		p.BaseType.Implements(destType) &&
+		!p.ElemType.Implements(destType) {
+
+		ifaceValue := reflect.New(destType).Elem()
+		ifaceValue.Set(p.BaseValue)
+		return ifaceValue, nil
+	}
+
 	destValue, err := p.convert(destElemType, destType)
 	if err != nil {
 		return reflect.Value{}, err
@@ -87,6 +150,12 @@ func (p Converter) Convert(destType reflect.Type) (reflect.Value, error) {
 }
 
 func (p Converter) convert(destElemType reflect.Type, destType reflect.Type) (reflect.Value, error) {
+	if destElemType.Kind() == reflect.Interface && p.ElemType.Kind() == reflect.Map {
+		if discriminatorKey := getUnionDiscriminatorKey(destElemType); discriminatorKey != "" {
+			return p.convertUnion(destElemType, discriminatorKey)
+		}
+	}
+
 	if p.ElemType.Kind() == reflect.Map &&
 		destElemType.Kind() == reflect.Map &&
 		p.ElemType != destElemType {
@@ -94,6 +163,41 @@ func (p Converter) convert(destElemType reflect.Type, destType reflect.Type) (re
 		return p.convertMap(destElemType, destType)
 	}
 
+	if p.ElemType == timeType || destElemType == timeType {
+		if convertedValue, ok, err := p.convertTime(destElemType); ok {
+			return convertedValue, err
+		}
+	}
+
+	if p.ElemType == durationType || destElemType == durationType {
+		if convertedValue, ok, err := p.convertDuration(destElemType); ok {
+			return convertedValue, err
+		}
+	}
+
+	if p.ElemType.Kind() == reflect.Map && destElemType.Kind() == reflect.Struct {
+		return p.convertMapToStruct(destElemType, destType)
+	}
+
+	if isSequence(p.ElemType) && isSequence(destElemType) {
+		return p.convertSequence(destElemType, destType)
+	}
+
+	// reflect.Value.Convert() would convert numbers into strings by
+	// interpreting them as unicode code points, e.g. 65 into "A",
+	// so we format them as decimal numbers instead:
+	if destElemType.Kind() == reflect.String && getNumericKind(p.ElemType) != notNumeric {
+		return reflect.ValueOf(formatNumber(p.ElemValue)).Convert(destElemType), nil
+	}
+
+	// Strings are converted to and from []byte as UTF-8 bytes
+	// and to and from []rune as unicode code points:
+	if p.ElemType.Kind() == reflect.String && isByteOrRuneSlice(destElemType) ||
+		isByteOrRuneSlice(p.ElemType) && destElemType.Kind() == reflect.String {
+
+		return p.ElemValue.Convert(destElemType), nil
+	}
+
 	if !p.ElemType.ConvertibleTo(destElemType) {
 		return reflect.Value{}, fmt.Errorf(
 			"cannot convert from type %v to type %v, received value was: %v",
@@ -101,6 +205,13 @@ func (p Converter) convert(destElemType reflect.Type, destType reflect.Type) (re
 		)
 	}
 
+	if !p.LenientNumbers && !isLenientNumericConversion() {
+		err := checkNumericConversion(p.ElemValue, destElemType)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
 	return p.ElemValue.Convert(destElemType), nil
 }
 
@@ -113,30 +224,183 @@ func (p Converter) convertMap(destElemType reflect.Type, destType reflect.Type)
 	for iter.Next() {
 		key := iter.Key()
 		value := iter.Value()
-		if key.Type().Kind() == reflect.Interface && !key.IsNil() {
-			key = key.Elem()
-		}
 
-		if value.Type().Kind() == reflect.Interface && !value.IsNil() {
-			value = value.Elem()
+		if isNilInterface(key) {
+			return reflect.Value{}, fmt.Errorf(
+				"cannot convert nil map key to target map key of type: %v",
+				destElemKeyType,
+			)
 		}
 
-		if !key.Type().ConvertibleTo(destElemKeyType) {
+		convertedKey, err := p.nested(key.Interface()).Convert(destElemKeyType)
+		if err != nil {
 			return reflect.Value{}, fmt.Errorf(
-				"cannot convert map key '%v' of type %v to target map key of type: %v",
-				key, key.Type(), destElemKeyType,
+				"cannot convert map key '%v' of type %v to target map key of type %v: %w",
+				key, key.Type(), destElemKeyType, err,
 			)
 		}
 
-		if !value.Type().ConvertibleTo(destElemValueType) {
+		if isNilInterface(value) && !isNillable(destElemValueType) {
+			return reflect.Value{}, WithPathPrefix(fmt.Sprintf("[%v]", key), fmt.Errorf(
+				"cannot convert nil map value to type: %v", destElemValueType,
+			))
+		}
+
+		convertedValue, err := p.nested(value.Interface()).Convert(destElemValueType)
+		if err != nil {
+			return reflect.Value{}, WithPathPrefix(fmt.Sprintf("[%v]", key), err)
+		}
+
+		targetMap.SetMapIndex(convertedKey, convertedValue)
+	}
+
+	return targetMap, nil
+}
+
+// convertMapToStruct fills the exported fields of a struct with the
+// values of a map, matching the map keys with the names from the
+// `map` and `json` tags or, if no tag matches, with the field names
+// ignoring the case.
+//
+// Map keys that don't match any field are ignored.
+func (p Converter) convertMapToStruct(destElemType reflect.Type, destType reflect.Type) (reflect.Value, error) {
+	keyKind := p.ElemType.Key().Kind()
+	if keyKind != reflect.String && keyKind != reflect.Interface {
+		return reflect.Value{}, fmt.Errorf(
+			"cannot convert from type %v to type %v: map keys must be strings",
+			p.BaseType, destType,
+		)
+	}
+
+	values := map[string]reflect.Value{}
+	lowerCaseValues := map[string]reflect.Value{}
+	iter := p.ElemValue.MapRange()
+	for iter.Next() {
+		key := iter.Key()
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if key.Kind() != reflect.String {
+			continue
+		}
+
+		values[key.String()] = iter.Value()
+		lowerCaseValues[strings.ToLower(key.String())] = iter.Value()
+	}
+
+	targetStruct := reflect.New(destElemType).Elem()
+	for i := 0; i < destElemType.NumField(); i++ {
+		field := destElemType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		value, found := lookupFieldValue(field, values, lowerCaseValues)
+		if !found || isNilInterface(value) {
+			continue
+		}
+
+		converter := p.nested(value.Interface())
+		if layout := field.Tag.Get("layout"); layout != "" {
+			converter = converter.WithTimeLayout(layout)
+		}
+
+		var convertedValue reflect.Value
+		var err error
+		if discriminatorKey := field.Tag.Get("union"); discriminatorKey != "" {
+			convertedValue, err = converter.ConvertUnion(field.Type, discriminatorKey)
+		} else {
+			convertedValue, err = converter.Convert(field.Type)
+		}
+		if err != nil {
+			return reflect.Value{}, WithPathPrefix(field.Name, err)
+		}
+
+		targetStruct.Field(i).Set(convertedValue)
+	}
+
+	return targetStruct, nil
+}
+
+func lookupFieldValue(
+	field reflect.StructField,
+	values map[string]reflect.Value,
+	lowerCaseValues map[string]reflect.Value,
+) (reflect.Value, bool) {
+	for _, tagName := range []string{"map", "json"} {
+		name, _ := tags.SplitOptions(field.Tag.Get(tagName))
+		if name == "" || name == "-" {
+			continue
+		}
+
+		value, found := values[name]
+		if found {
+			return value, true
+		}
+	}
+
+	value, found := lowerCaseValues[strings.ToLower(field.Name)]
+	return value, found
+}
+
+func isNilInterface(v reflect.Value) bool {
+	return v.Kind() == reflect.Interface && v.IsNil()
+}
+
+func isNillable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return true
+	}
+	return false
+}
+
+func isByteOrRuneSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+
+	elemKind := t.Elem().Kind()
+	return elemKind == reflect.Uint8 || elemKind == reflect.Int32
+}
+
+func isSequence(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+}
+
+// convertSequence converts slices and arrays item by item.
+//
+// Note that slices are always copied even if
+// the source and target types are the same.
+func (p Converter) convertSequence(destElemType reflect.Type, destType reflect.Type) (reflect.Value, error) {
+	if p.ElemType.Kind() == reflect.Slice && p.ElemValue.IsNil() {
+		return reflect.Zero(destElemType), nil
+	}
+
+	length := p.ElemValue.Len()
+
+	var targetSeq reflect.Value
+	if destElemType.Kind() == reflect.Slice {
+		targetSeq = reflect.MakeSlice(destElemType, length, length)
+	} else {
+		if length > destElemType.Len() {
 			return reflect.Value{}, fmt.Errorf(
-				"cannot convert map value: '%v' of type: %v, on key: '%v', to type: %v",
-				value, value.Type(), key, destElemValueType,
+				"cannot convert %d items into array of type %v, received value was: %v",
+				length, destType, p.ElemValue,
 			)
 		}
+		targetSeq = reflect.New(destElemType).Elem()
+	}
+
+	itemType := destElemType.Elem()
+	for i := 0; i < length; i++ {
+		convertedValue, err := p.nested(p.ElemValue.Index(i).Interface()).Convert(itemType)
+		if err != nil {
+			return reflect.Value{}, WithPathPrefix(fmt.Sprintf("[%d]", i), err)
+		}
 
-		targetMap.SetMapIndex(key.Convert(destElemKeyType), value.Convert(destElemValueType))
+		targetSeq.Index(i).Set(convertedValue)
 	}
 
-	return targetMap, nil
+	return targetSeq, nil
 }
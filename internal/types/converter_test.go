@@ -115,6 +115,190 @@ func TestConverter(t *testing.T) {
 			targetType:         reflect.TypeOf(map[string]string{}),
 			expectErrToContain: []string{"cannot convert", "nil", "to", "string"},
 		},
+		{
+			desc:           "should convert slices into arrays",
+			input:          []any{1, 2},
+			targetType:     reflect.TypeOf([3]float64{}),
+			expectedOutput: [3]float64{1, 2, 0},
+		},
+		{
+			desc:           "should convert arrays into slices",
+			input:          [3]int{1, 2, 3},
+			targetType:     reflect.TypeOf([]int64{}),
+			expectedOutput: []int64{1, 2, 3},
+		},
+		{
+			desc:           "should convert arrays into arrays of different lengths",
+			input:          [2]int{1, 2},
+			targetType:     reflect.TypeOf([3]int{}),
+			expectedOutput: [3]int{1, 2, 0},
+		},
+		{
+			desc:           "should return nil for nil slices",
+			input:          []any(nil),
+			targetType:     reflect.TypeOf([]int{}),
+			expectedOutput: []int(nil),
+		},
+		{
+			desc:               "should report error if the slice doesn't fit on the array",
+			input:              []int{1, 2, 3},
+			targetType:         reflect.TypeOf([2]int{}),
+			expectErrToContain: []string{"cannot convert", "3 items", "[2]int"},
+		},
+		{
+			desc:               "should report error if one of the items is not compatible",
+			input:              []any{1, "notANumber"},
+			targetType:         reflect.TypeOf([2]int{}),
+			expectErrToContain: []string{"[1]", "string", "int", "notANumber"},
+		},
+		{
+			desc:           "should format ints as decimal numbers when converting to strings",
+			input:          65,
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "65",
+		},
+		{
+			desc:           "should format runes as decimal numbers when converting to strings",
+			input:          'A',
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "65",
+		},
+		{
+			desc:           "should format unsigned ints as decimal numbers when converting to strings",
+			input:          uint8(200),
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "200",
+		},
+		{
+			desc:           "should format floats when converting to strings",
+			input:          float32(1.5),
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "1.5",
+		},
+		{
+			desc:           "should format numbers when converting to named string types",
+			input:          intPtr(42),
+			targetType:     reflect.TypeOf(namedString("")),
+			expectedOutput: namedString("42"),
+		},
+		{
+			desc:           "should convert strings into byte slices as UTF-8 bytes",
+			input:          "ñ",
+			targetType:     reflect.TypeOf([]byte{}),
+			expectedOutput: []byte{0xc3, 0xb1},
+		},
+		{
+			desc:           "should convert byte slices into strings",
+			input:          []byte("foo"),
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "foo",
+		},
+		{
+			desc:           "should convert strings into rune slices as code points",
+			input:          "ñA",
+			targetType:     reflect.TypeOf([]rune{}),
+			expectedOutput: []rune{'ñ', 'A'},
+		},
+		{
+			desc:           "should convert rune slices into strings",
+			input:          []rune{'ñ', 'A'},
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "ñA",
+		},
+		{
+			desc:               "should not convert strings into other slice types",
+			input:              "123",
+			targetType:         reflect.TypeOf([]int{}),
+			expectErrToContain: []string{"cannot convert", "string", "[]int", "123"},
+		},
+		{
+			desc:               "should not convert strings into numbers",
+			input:              "65",
+			targetType:         reflect.TypeOf(0),
+			expectErrToContain: []string{"cannot convert", "string", "int", "65"},
+		},
+		{
+			desc:               "should not convert bools into strings",
+			input:              true,
+			targetType:         reflect.TypeOf(""),
+			expectErrToContain: []string{"cannot convert", "bool", "string", "true"},
+		},
+		{
+			desc:           "should convert slices of slices",
+			input:          [][]any{{1, 2}, {3.0}},
+			targetType:     reflect.TypeOf([][]int{}),
+			expectedOutput: [][]int{{1, 2}, {3}},
+		},
+		{
+			desc:           "should dereference pointers inside slices of interfaces",
+			input:          []any{intPtr(1), 2, (*int)(nil)},
+			targetType:     reflect.TypeOf([]int{}),
+			expectedOutput: []int{1, 2, 0},
+		},
+		{
+			desc: "should convert slices of maps into slices of structs",
+			input: []map[string]any{
+				{"name": "fakeName", "Price": 10, "ignored": true},
+				{"NAME": "otherName", "tags": []any{"a"}},
+			},
+			targetType: reflect.TypeOf([]item{}),
+			expectedOutput: []item{
+				{Name: "fakeName", Price: 10},
+				{Name: "otherName", Tags: []string{"a"}},
+			},
+		},
+		{
+			desc: "should convert maps of slices",
+			input: map[string]any{
+				"fakeKey": []any{1, 2},
+			},
+			targetType: reflect.TypeOf(map[string][]float64{}),
+			expectedOutput: map[string][]float64{
+				"fakeKey": {1, 2},
+			},
+		},
+		{
+			desc: "should convert maps of maps into maps of structs",
+			input: map[string]map[string]any{
+				"fakeKey": {"name": "fakeName", "price": 10},
+			},
+			targetType: reflect.TypeOf(map[string]*item{}),
+			expectedOutput: map[string]*item{
+				"fakeKey": {Name: "fakeName", Price: 10},
+			},
+		},
+		{
+			desc: "should prefer tag names over field names when converting maps to structs",
+			input: map[string]any{
+				"Name":       "fieldName",
+				"other_name": "tagName",
+			},
+			targetType:     reflect.TypeOf(tagged{}),
+			expectedOutput: tagged{Name: "tagName"},
+		},
+		{
+			desc: "should report the path of errors on nested values",
+			input: []any{
+				map[string]any{},
+				map[string]any{"price": "notANumber"},
+			},
+			targetType:         reflect.TypeOf([]item{}),
+			expectErrToContain: []string{"error converting [1].Price", "string", "float64", "notANumber"},
+		},
+		{
+			desc: "should report the path of errors on nested maps",
+			input: map[string]any{
+				"fakeKey": map[string]any{"tags": []any{"a", struct{}{}}},
+			},
+			targetType:         reflect.TypeOf(map[string]item{}),
+			expectErrToContain: []string{"error converting [fakeKey].Tags[1]", "struct", "string"},
+		},
+		{
+			desc:               "should report error if map keys are not strings when converting to structs",
+			input:              map[int]any{1: "fakeValue"},
+			targetType:         reflect.TypeOf(item{}),
+			expectErrToContain: []string{"cannot convert", "map[int]interface {}", "item", "keys must be strings"},
+		},
 	}
 
 	for _, test := range tests {
@@ -131,6 +315,18 @@ func TestConverter(t *testing.T) {
 	}
 }
 
+type namedString string
+
+type item struct {
+	Name  string `json:"name"`
+	Price float64
+	Tags  []string `map:"tags"`
+}
+
+type tagged struct {
+	Name string `map:"other_name"`
+}
+
 func intPtr(i int) *int {
 	return &i
 }
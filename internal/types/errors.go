@@ -0,0 +1,40 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PathError describes a conversion error on a nested item of the
+// converted value, e.g. on the field `Price` of the item 3 of the
+// slice `Items` the Path would be `Items[3].Price`.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("error converting %s: %s", e.Path, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// WithPathPrefix adds a prefix to the path of the error,
+// wrapping it in a PathError if necessary.
+//
+// The prefix can be either a field name or an index like `[3]`.
+func WithPathPrefix(prefix string, err error) error {
+	pathErr, ok := err.(*PathError)
+	if !ok {
+		return &PathError{Path: prefix, Err: err}
+	}
+
+	path := pathErr.Path
+	if !strings.HasPrefix(path, "[") {
+		path = "." + path
+	}
+
+	return &PathError{Path: prefix + path, Err: pathErr.Err}
+}
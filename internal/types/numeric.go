@@ -0,0 +1,149 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+var lenientNumericConversion int32
+
+// SetLenientNumericConversion controls whether numeric conversions that
+// overflow, lose the sign or lose the fractional part of the value should
+// be allowed, which was the behavior of the Converter before the strict
+// checks were introduced.
+//
+// This affects every Converter in the process, prefer
+// WithLenientNumbers() for enabling it on a single conversion.
+func SetLenientNumericConversion(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&lenientNumericConversion, v)
+}
+
+func isLenientNumericConversion() bool {
+	return atomic.LoadInt32(&lenientNumericConversion) == 1
+}
+
+type numericKind int
+
+const (
+	notNumeric numericKind = iota
+	signedKind
+	unsignedKind
+	floatKind
+)
+
+func getNumericKind(t reflect.Type) numericKind {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return signedKind
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return unsignedKind
+	case reflect.Float32, reflect.Float64:
+		return floatKind
+	}
+	return notNumeric
+}
+
+// checkNumericConversion returns an error if converting the
+// numeric value to destType would not preserve its value.
+//
+// It returns nil for non-numeric values.
+func checkNumericConversion(value reflect.Value, destType reflect.Type) error {
+	srcKind := getNumericKind(value.Type())
+	destKind := getNumericKind(destType)
+	if srcKind == notNumeric || destKind == notNumeric {
+		return nil
+	}
+
+	dest := reflect.New(destType).Elem()
+
+	var reason string
+	switch srcKind {
+	case signedKind:
+		i := value.Int()
+		switch destKind {
+		case signedKind:
+			if dest.OverflowInt(i) {
+				reason = "overflowing"
+			}
+		case unsignedKind:
+			if i < 0 {
+				reason = "losing its sign"
+			} else if dest.OverflowUint(uint64(i)) {
+				reason = "overflowing"
+			}
+		case floatKind:
+			if int64(value.Convert(destType).Float()) != i {
+				reason = "losing precision"
+			}
+		}
+
+	case unsignedKind:
+		u := value.Uint()
+		switch destKind {
+		case signedKind:
+			if u > math.MaxInt64 || dest.OverflowInt(int64(u)) {
+				reason = "overflowing"
+			}
+		case unsignedKind:
+			if dest.OverflowUint(u) {
+				reason = "overflowing"
+			}
+		case floatKind:
+			f := value.Convert(destType).Float()
+			if f >= math.MaxUint64 || uint64(f) != u {
+				reason = "losing precision"
+			}
+		}
+
+	case floatKind:
+		f := value.Float()
+		switch destKind {
+		case signedKind:
+			if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 || dest.OverflowInt(int64(f)) {
+				reason = "overflowing"
+			} else if f != math.Trunc(f) {
+				reason = "losing its fractional part"
+			}
+		case unsignedKind:
+			if f < 0 {
+				reason = "losing its sign"
+			} else if math.IsNaN(f) || f >= math.MaxUint64 || dest.OverflowUint(uint64(f)) {
+				reason = "overflowing"
+			} else if f != math.Trunc(f) {
+				reason = "losing its fractional part"
+			}
+		case floatKind:
+			if !math.IsInf(f, 0) && dest.OverflowFloat(f) {
+				reason = "overflowing"
+			}
+		}
+	}
+
+	if reason == "" {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"cannot convert %v of type %v to type %v without %s",
+		value, value.Type(), destType, reason,
+	)
+}
+
+// formatNumber formats numeric values as decimal numbers
+func formatNumber(value reflect.Value) string {
+	switch getNumericKind(value.Type()) {
+	case signedKind:
+		return strconv.FormatInt(value.Int(), 10)
+	case unsignedKind:
+		return strconv.FormatUint(value.Uint(), 10)
+	default:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits())
+	}
+}
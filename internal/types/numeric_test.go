@@ -0,0 +1,143 @@
+package types
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func TestNumericConversion(t *testing.T) {
+	tests := []struct {
+		desc               string
+		input              any
+		targetType         reflect.Type
+		expectedOutput     any
+		expectErrToContain []string
+	}{
+		{
+			desc:           "should convert ints that fit on the target type",
+			input:          255,
+			targetType:     reflect.TypeOf(uint8(0)),
+			expectedOutput: uint8(255),
+		},
+		{
+			desc:           "should convert floats without fractional part into ints",
+			input:          3.0,
+			targetType:     reflect.TypeOf(int16(0)),
+			expectedOutput: int16(3),
+		},
+		{
+			desc:           "should convert ints into floats",
+			input:          int64(1 << 53),
+			targetType:     reflect.TypeOf(float64(0)),
+			expectedOutput: float64(1 << 53),
+		},
+		{
+			desc:           "should convert between float types",
+			input:          1.5,
+			targetType:     reflect.TypeOf(float32(0)),
+			expectedOutput: float32(1.5),
+		},
+		{
+			desc:               "should report overflows of signed ints",
+			input:              300,
+			targetType:         reflect.TypeOf(int8(0)),
+			expectErrToContain: []string{"300", "int8", "overflowing"},
+		},
+		{
+			desc:               "should report overflows of unsigned ints",
+			input:              300,
+			targetType:         reflect.TypeOf(uint8(0)),
+			expectErrToContain: []string{"300", "uint8", "overflowing"},
+		},
+		{
+			desc:               "should report overflows from unsigned to signed ints",
+			input:              uint64(math.MaxUint64),
+			targetType:         reflect.TypeOf(int64(0)),
+			expectErrToContain: []string{"uint64", "int64", "overflowing"},
+		},
+		{
+			desc:               "should report sign loss",
+			input:              -1,
+			targetType:         reflect.TypeOf(uint(0)),
+			expectErrToContain: []string{"-1", "uint", "losing its sign"},
+		},
+		{
+			desc:               "should report sign loss from floats",
+			input:              -1.0,
+			targetType:         reflect.TypeOf(uint32(0)),
+			expectErrToContain: []string{"-1", "uint32", "losing its sign"},
+		},
+		{
+			desc:               "should report fractional truncation",
+			input:              3.9,
+			targetType:         reflect.TypeOf(0),
+			expectErrToContain: []string{"3.9", "float64", "int", "losing its fractional part"},
+		},
+		{
+			desc:               "should report overflows from floats into ints",
+			input:              1e20,
+			targetType:         reflect.TypeOf(int64(0)),
+			expectErrToContain: []string{"int64", "overflowing"},
+		},
+		{
+			desc:               "should report NaN conversions into ints",
+			input:              math.NaN(),
+			targetType:         reflect.TypeOf(0),
+			expectErrToContain: []string{"NaN", "overflowing"},
+		},
+		{
+			desc:               "should report overflows between float types",
+			input:              1e300,
+			targetType:         reflect.TypeOf(float32(0)),
+			expectErrToContain: []string{"float32", "overflowing"},
+		},
+		{
+			desc:               "should report precision loss from ints to floats",
+			input:              int64(1<<53 + 1),
+			targetType:         reflect.TypeOf(float64(0)),
+			expectErrToContain: []string{"9007199254740993", "float64", "losing precision"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			v, err := NewConverter(test.input).Convert(test.targetType)
+			if test.expectErrToContain != nil {
+				tt.AssertErrContains(t, err, test.expectErrToContain...)
+				t.Skip()
+			}
+
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, v.Interface(), test.expectedOutput)
+		})
+	}
+
+	t.Run("should allow lossy conversions with WithLenientNumbers", func(t *testing.T) {
+		v, err := NewConverter(300).WithLenientNumbers(true).Convert(reflect.TypeOf(uint8(0)))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, v.Interface(), uint8(44))
+
+		v, err = NewConverter([]float64{3.9}).WithLenientNumbers(true).Convert(reflect.TypeOf([]int{}))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, v.Interface(), []int{3})
+
+		_, err = NewConverter(300).Convert(reflect.TypeOf(uint8(0)))
+		tt.AssertErrContains(t, err, "300", "uint8")
+	})
+
+	t.Run("should allow lossy conversions in lenient mode", func(t *testing.T) {
+		SetLenientNumericConversion(true)
+		defer SetLenientNumericConversion(false)
+
+		v, err := NewConverter(300).Convert(reflect.TypeOf(uint8(0)))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, v.Interface(), uint8(44))
+
+		v, err = NewConverter(3.9).Convert(reflect.TypeOf(0))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, v.Interface(), 3)
+	})
+}
@@ -38,6 +38,17 @@ func StringToType(t reflect.Type, v string) (reflect.Value, error) {
 	case reflect.Uint64:
 		i, err := strconv.ParseUint(v, 10, 64)
 		return reflect.ValueOf(uint64(i)), err
+
+	case reflect.Float32:
+		f, err := strconv.ParseFloat(v, 32)
+		return reflect.ValueOf(float32(f)), err
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(v, 64)
+		return reflect.ValueOf(f), err
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(v)
+		return reflect.ValueOf(b), err
 	}
 
 	return reflect.ValueOf(v), nil
@@ -0,0 +1,143 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// unixLayouts maps the special layouts for Unix timestamps
+// to the duration of their units
+var unixLayouts = map[string]time.Duration{
+	"unix":      time.Second,
+	"unixmilli": time.Millisecond,
+	"unixmicro": time.Microsecond,
+	"unixnano":  time.Nanosecond,
+}
+
+// convertTime handles the conversions of strings and numbers to and from
+// time.Time, the returned bool is false if the conversion is not one of these.
+func (p Converter) convertTime(destElemType reflect.Type) (reflect.Value, bool, error) {
+	layout := p.TimeLayout
+	unit, isUnixLayout := unixLayouts[layout]
+
+	srcNumericKind := getNumericKind(p.ElemType)
+	switch {
+	case p.ElemType.Kind() == reflect.String && destElemType == timeType:
+		s := p.ElemValue.String()
+		if isUnixLayout {
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return reflect.Value{}, true, fmt.Errorf("cannot parse '%s' as time.Time with layout '%s': %w", s, layout, err)
+			}
+			return reflect.ValueOf(unixToTime(i, unit)), true, nil
+		}
+
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return reflect.Value{}, true, fmt.Errorf("cannot parse '%s' as time.Time with layout '%s': %w", s, layout, err)
+		}
+		return reflect.ValueOf(t), true, nil
+
+	case srcNumericKind != notNumeric && destElemType == timeType:
+		if !isUnixLayout {
+			unit = time.Second
+		}
+
+		switch srcNumericKind {
+		case signedKind:
+			return reflect.ValueOf(unixToTime(p.ElemValue.Int(), unit)), true, nil
+		case unsignedKind:
+			u := p.ElemValue.Uint()
+			if u > math.MaxInt64 {
+				return reflect.Value{}, true, fmt.Errorf("cannot convert %d to time.Time without overflowing", u)
+			}
+			return reflect.ValueOf(unixToTime(int64(u), unit)), true, nil
+		default:
+			nanoseconds := p.ElemValue.Float() * float64(unit)
+			if math.IsNaN(nanoseconds) || nanoseconds < math.MinInt64 || nanoseconds >= math.MaxInt64 {
+				return reflect.Value{}, true, fmt.Errorf("cannot convert %v to time.Time without overflowing", p.ElemValue)
+			}
+			return reflect.ValueOf(time.Unix(0, int64(nanoseconds)).UTC()), true, nil
+		}
+
+	case p.ElemType == timeType && destElemType.Kind() == reflect.String:
+		t := p.ElemValue.Interface().(time.Time)
+		if isUnixLayout {
+			s := strconv.FormatInt(timeToUnix(t, unit), 10)
+			return reflect.ValueOf(s).Convert(destElemType), true, nil
+		}
+
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return reflect.ValueOf(t.Format(layout)).Convert(destElemType), true, nil
+
+	case p.ElemType == timeType && getNumericKind(destElemType) != notNumeric:
+		if !isUnixLayout {
+			unit = time.Second
+		}
+
+		t := p.ElemValue.Interface().(time.Time)
+		convertedValue, err := p.nested(timeToUnix(t, unit)).Convert(destElemType)
+		return convertedValue, true, err
+	}
+
+	return reflect.Value{}, false, nil
+}
+
+func unixToTime(i int64, unit time.Duration) time.Time {
+	switch unit {
+	case time.Millisecond:
+		return time.UnixMilli(i).UTC()
+	case time.Microsecond:
+		return time.UnixMicro(i).UTC()
+	case time.Nanosecond:
+		return time.Unix(0, i).UTC()
+	}
+	return time.Unix(i, 0).UTC()
+}
+
+func timeToUnix(t time.Time, unit time.Duration) int64 {
+	switch unit {
+	case time.Millisecond:
+		return t.UnixMilli()
+	case time.Microsecond:
+		return t.UnixMicro()
+	case time.Nanosecond:
+		return t.UnixNano()
+	}
+	return t.Unix()
+}
+
+// convertDuration handles the conversions of strings to and from
+// time.Duration, the returned bool is false if the conversion is not
+// one of these.
+//
+// Numbers are converted to time.Duration as nanoseconds
+// by the default conversion rules.
+func (p Converter) convertDuration(destElemType reflect.Type) (reflect.Value, bool, error) {
+	switch {
+	case p.ElemType.Kind() == reflect.String && destElemType == durationType:
+		d, err := time.ParseDuration(p.ElemValue.String())
+		if err != nil {
+			return reflect.Value{}, true, fmt.Errorf("cannot parse '%s' as time.Duration: %w", p.ElemValue.String(), err)
+		}
+		return reflect.ValueOf(d), true, nil
+
+	case p.ElemType == durationType && destElemType.Kind() == reflect.String:
+		d := time.Duration(p.ElemValue.Int())
+		return reflect.ValueOf(d.String()).Convert(destElemType), true, nil
+	}
+
+	return reflect.Value{}, false, nil
+}
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func TestTimeConversion(t *testing.T) {
+	date := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		desc               string
+		input              any
+		layout             string
+		targetType         reflect.Type
+		expectedOutput     any
+		expectErrToContain []string
+	}{
+		{
+			desc:           "should parse strings as RFC3339 by default",
+			input:          "2024-03-15T10:30:00Z",
+			targetType:     reflect.TypeOf(time.Time{}),
+			expectedOutput: date,
+		},
+		{
+			desc:           "should parse strings with custom layouts",
+			input:          "15/03/2024 10:30",
+			layout:         "02/01/2006 15:04",
+			targetType:     reflect.TypeOf(&time.Time{}),
+			expectedOutput: &date,
+		},
+		{
+			desc:           "should parse strings with unix layouts",
+			input:          "1710498600000",
+			layout:         "unixmilli",
+			targetType:     reflect.TypeOf(time.Time{}),
+			expectedOutput: date,
+		},
+		{
+			desc:           "should convert numbers as unix seconds by default",
+			input:          int64(1710498600),
+			targetType:     reflect.TypeOf(time.Time{}),
+			expectedOutput: date,
+		},
+		{
+			desc:           "should convert numbers with unix layouts",
+			input:          uint64(1710498600000000),
+			layout:         "unixmicro",
+			targetType:     reflect.TypeOf(time.Time{}),
+			expectedOutput: date,
+		},
+		{
+			desc:           "should convert floats as fractional unix seconds",
+			input:          1710498600.5,
+			targetType:     reflect.TypeOf(time.Time{}),
+			expectedOutput: date.Add(500 * time.Millisecond),
+		},
+		{
+			desc:           "should format times as RFC3339 strings by default",
+			input:          date,
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "2024-03-15T10:30:00Z",
+		},
+		{
+			desc:           "should format times with custom layouts",
+			input:          &date,
+			layout:         "2006-01-02",
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "2024-03-15",
+		},
+		{
+			desc:           "should format times with unix layouts",
+			input:          date,
+			layout:         "unixmilli",
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "1710498600000",
+		},
+		{
+			desc:           "should convert times into numbers",
+			input:          date,
+			targetType:     reflect.TypeOf(int64(0)),
+			expectedOutput: int64(1710498600),
+		},
+		{
+			desc:               "should report overflows when converting times into numbers",
+			input:              date,
+			layout:             "unixnano",
+			targetType:         reflect.TypeOf(int32(0)),
+			expectErrToContain: []string{"int32", "overflowing"},
+		},
+		{
+			desc:               "should report invalid times",
+			input:              "15/03/2024",
+			targetType:         reflect.TypeOf(time.Time{}),
+			expectErrToContain: []string{"cannot parse", "15/03/2024", "time.Time", time.RFC3339},
+		},
+		{
+			desc:               "should report invalid unix times",
+			input:              "notANumber",
+			layout:             "unix",
+			targetType:         reflect.TypeOf(time.Time{}),
+			expectErrToContain: []string{"cannot parse", "notANumber", "time.Time", "unix"},
+		},
+		{
+			desc:           "should parse durations",
+			input:          "1h30m",
+			targetType:     reflect.TypeOf(time.Duration(0)),
+			expectedOutput: 90 * time.Minute,
+		},
+		{
+			desc:           "should convert numbers into durations as nanoseconds",
+			input:          1000,
+			targetType:     reflect.TypeOf(time.Duration(0)),
+			expectedOutput: time.Microsecond,
+		},
+		{
+			desc:           "should format durations as strings",
+			input:          90 * time.Minute,
+			targetType:     reflect.TypeOf(""),
+			expectedOutput: "1h30m0s",
+		},
+		{
+			desc:               "should report invalid durations",
+			input:              "90 minutes",
+			targetType:         reflect.TypeOf(time.Duration(0)),
+			expectErrToContain: []string{"cannot parse", "90 minutes", "time.Duration"},
+		},
+		{
+			desc: "should use the layout tag of nested struct fields",
+			input: map[string]any{
+				"Default": "2024-03-15T10:30:00Z",
+				"Custom":  "2024-03-15",
+			},
+			targetType: reflect.TypeOf(struct {
+				Default time.Time
+				Custom  time.Time `layout:"2006-01-02"`
+			}{}),
+			expectedOutput: struct {
+				Default time.Time
+				Custom  time.Time `layout:"2006-01-02"`
+			}{
+				Default: date,
+				Custom:  time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			v, err := NewConverter(test.input).WithTimeLayout(test.layout).Convert(test.targetType)
+			if test.expectErrToContain != nil {
+				tt.AssertErrContains(t, err, test.expectErrToContain...)
+				t.Skip()
+			}
+
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, v.Interface(), test.expectedOutput)
+		})
+	}
+}
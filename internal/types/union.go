@@ -0,0 +1,168 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type unionInfo struct {
+	// discriminatorKey is the map key used for decoding
+	// the interface when no key is explicitly informed
+	discriminatorKey string
+
+	concreteTypes map[string]reflect.Type
+}
+
+// unions maps interface types to the information
+// necessary for decoding maps into them
+var unions = map[reflect.Type]*unionInfo{}
+var unionsMutex sync.RWMutex
+
+// RegisterUnionType registers the concreteType as the implementation of
+// ifaceType to be used when decoding maps with the given discriminator value.
+func RegisterUnionType(ifaceType reflect.Type, discriminator string, concreteType reflect.Type) {
+	unionsMutex.Lock()
+	defer unionsMutex.Unlock()
+
+	getOrCreateUnion(ifaceType).concreteTypes[discriminator] = concreteType
+}
+
+// SetUnionDiscriminatorKey sets the map key that holds the discriminator
+// of the ifaceType, which allows the Converter to decode maps into
+// this interface everywhere, e.g. on slices and nested structs.
+func SetUnionDiscriminatorKey(ifaceType reflect.Type, discriminatorKey string) {
+	unionsMutex.Lock()
+	defer unionsMutex.Unlock()
+
+	getOrCreateUnion(ifaceType).discriminatorKey = discriminatorKey
+}
+
+func getOrCreateUnion(ifaceType reflect.Type) *unionInfo {
+	union, found := unions[ifaceType]
+	if !found {
+		union = &unionInfo{
+			concreteTypes: map[string]reflect.Type{},
+		}
+		unions[ifaceType] = union
+	}
+	return union
+}
+
+// getUnionDiscriminatorKey returns an empty string if
+// no discriminator key was registered for the ifaceType
+func getUnionDiscriminatorKey(ifaceType reflect.Type) string {
+	unionsMutex.RLock()
+	defer unionsMutex.RUnlock()
+
+	union, found := unions[ifaceType]
+	if !found {
+		return ""
+	}
+	return union.discriminatorKey
+}
+
+// lookupUnionType returns the concrete type registered for the discriminator
+// or the sorted list of the discriminators registered for the ifaceType.
+func lookupUnionType(ifaceType reflect.Type, discriminator string) (reflect.Type, []string) {
+	unionsMutex.RLock()
+	defer unionsMutex.RUnlock()
+
+	union, found := unions[ifaceType]
+	if !found {
+		return nil, nil
+	}
+
+	concreteType, found := union.concreteTypes[discriminator]
+	if found {
+		return concreteType, nil
+	}
+
+	allowed := []string{}
+	for discriminator := range union.concreteTypes {
+		allowed = append(allowed, discriminator)
+	}
+	sort.Strings(allowed)
+
+	return nil, allowed
+}
+
+// ConvertUnion works like Convert() but if the source value is a map
+// it reads the value of the discriminatorKey from it and decodes the
+// map into the concrete type registered for that value.
+func (p Converter) ConvertUnion(ifaceType reflect.Type, discriminatorKey string) (reflect.Value, error) {
+	if ifaceType.Kind() != reflect.Interface || p.ElemType.Kind() != reflect.Map {
+		return p.Convert(ifaceType)
+	}
+
+	if p.BaseType.Kind() == reflect.Ptr && p.BaseValue.IsNil() {
+		return reflect.Zero(ifaceType), nil
+	}
+
+	return p.convertUnion(ifaceType, discriminatorKey)
+}
+
+func (p Converter) convertUnion(ifaceType reflect.Type, discriminatorKey string) (reflect.Value, error) {
+	if p.ElemValue.IsNil() {
+		return reflect.Zero(ifaceType), nil
+	}
+
+	discriminator, found, err := p.getDiscriminator(discriminatorKey)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	concreteType, allowed := lookupUnionType(ifaceType, discriminator)
+	if !found {
+		return reflect.Value{}, fmt.Errorf(
+			"missing discriminator '%s' for decoding %v, expected one of: %s",
+			discriminatorKey, ifaceType, strings.Join(allowed, ", "),
+		)
+	}
+
+	if concreteType == nil {
+		return reflect.Value{}, fmt.Errorf(
+			"unknown %s '%s' for %v, expected one of: %s",
+			discriminatorKey, discriminator, ifaceType, strings.Join(allowed, ", "),
+		)
+	}
+
+	concreteValue, err := p.Convert(concreteType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	ifaceValue := reflect.New(ifaceType).Elem()
+	ifaceValue.Set(concreteValue)
+	return ifaceValue, nil
+}
+
+// getDiscriminator returns false if the map has no discriminator key
+func (p Converter) getDiscriminator(discriminatorKey string) (string, bool, error) {
+	keyType := p.ElemType.Key()
+	if keyType.Kind() != reflect.String && keyType.Kind() != reflect.Interface {
+		return "", false, fmt.Errorf("cannot read discriminator '%s' from map of type %v: map keys must be strings", discriminatorKey, p.ElemType)
+	}
+
+	key := reflect.ValueOf(discriminatorKey)
+	if keyType.Kind() == reflect.String {
+		key = key.Convert(keyType)
+	}
+
+	value := p.ElemValue.MapIndex(key)
+	if value.IsValid() && value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	if !value.IsValid() {
+		return "", false, nil
+	}
+
+	if value.Kind() != reflect.String {
+		return "", false, fmt.Errorf("expected discriminator '%s' to be a string but got: %v", discriminatorKey, value.Type())
+	}
+
+	return value.String(), true, nil
+}
@@ -0,0 +1,183 @@
+package mapi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/vingarcia/structi/internal/types"
+)
+
+// IteratorFunc is the interface that allows the ForEach function to
+// read and update the items of any map type.
+type IteratorFunc func(field Field) error
+
+// Field is the input expected by the `IteratorFunc` and contains all
+// the information about the map item that is currently being targeted
+// by the ForEach() function.
+//
+// Since map values are not addressable, Value contains a copy of the
+// item value, so changes to the map should be done via Set() or Delete().
+type Field struct {
+	Key   any
+	Kind  reflect.Kind
+	Type  reflect.Type
+	Value any
+
+	Set    func(value any) error
+	Delete func()
+}
+
+// ForEachOptions allows the user to customize the behavior of ForEach()
+type ForEachOptions struct {
+	// SortKeys makes ForEach() iterate over the keys in ascending order
+	// instead of the random order of Go maps, which is useful for
+	// generating reproducible outputs.
+	SortKeys bool
+}
+
+// ForEach iterates over the map calling the iterate function
+// for each item.
+//
+// It is safe to call Set() and Delete() on any key during the iteration,
+// keys deleted before being visited are skipped.
+func ForEach(targetMap any, iterate IteratorFunc, opts ...ForEachOptions) error {
+	var o ForEachOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	t, v, err := getMapInfo(targetMap)
+	if err != nil {
+		return err
+	}
+
+	mapValue := v.Elem()
+	keys := mapValue.MapKeys()
+	if o.SortKeys {
+		sortKeys(keys)
+	}
+
+	valueType := t.Elem()
+	for _, key := range keys {
+		value := mapValue.MapIndex(key)
+		if !value.IsValid() {
+			continue
+		}
+
+		err := iterate(Field{
+			Key:   key.Interface(),
+			Kind:  valueType.Kind(),
+			Type:  valueType,
+			Value: value.Interface(),
+
+			Set: setItemValue(mapValue, key),
+			Delete: func() {
+				mapValue.SetMapIndex(key, reflect.Value{})
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("iteration error on key '%v' of type '%v': %w", key, valueType, err)
+		}
+	}
+
+	return nil
+}
+
+// Put converts the key and value to the key and value types of the
+// map and then stores them on it, allocating the map if it is nil.
+func Put(targetMap any, key any, value any) error {
+	t, v, err := getMapInfo(targetMap)
+	if err != nil {
+		return err
+	}
+
+	convertedKey, err := types.NewConverter(key).Convert(t.Key())
+	if err != nil {
+		return fmt.Errorf("error converting key %+v to %v: %w", key, t.Key(), err)
+	}
+
+	mapValue := v.Elem()
+	if mapValue.IsNil() {
+		mapValue.Set(reflect.MakeMap(t))
+	}
+
+	return setItemValue(mapValue, convertedKey)(value)
+}
+
+func setItemValue(mapValue reflect.Value, key reflect.Value) func(value any) error {
+	return func(value any) error {
+		valueType := mapValue.Type().Elem()
+		convertedValue, err := types.NewConverter(value).Convert(valueType)
+		if err != nil {
+			return fmt.Errorf("error converting %v[%v]: %w", mapValue.Type(), key, err)
+		}
+
+		mapValue.SetMapIndex(key, convertedValue)
+		return nil
+	}
+}
+
+// sortKeys sorts the keys by their values if they are of an ordered
+// kind or by their string representation otherwise.
+//
+// Keys of `any` typed maps are grouped by kind before being sorted.
+func sortKeys(keys []reflect.Value) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Kind() == reflect.Interface {
+			a = a.Elem()
+		}
+		if b.Kind() == reflect.Interface {
+			b = b.Elem()
+		}
+
+		if a.Kind() != b.Kind() {
+			return a.Kind() < b.Kind()
+		}
+
+		if a.IsValid() {
+			switch a.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return a.Int() < b.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				return a.Uint() < b.Uint()
+			case reflect.Float32, reflect.Float64:
+				return a.Float() < b.Float()
+			case reflect.String:
+				return a.String() < b.String()
+			case reflect.Bool:
+				return !a.Bool() && b.Bool()
+			}
+		}
+
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	})
+}
+
+func getMapInfo(targetMap any) (reflect.Type, reflect.Value, error) {
+	if targetMap == nil {
+		return nil, reflect.Value{}, fmt.Errorf("unexpected nil input")
+	}
+
+	v, ok := targetMap.(reflect.Value)
+	if !ok {
+		v = reflect.ValueOf(targetMap)
+	}
+	ptrType := v.Type()
+
+	if ptrType.Kind() != reflect.Ptr {
+		return nil, reflect.Value{}, fmt.Errorf("expected map pointer but got: %v", ptrType)
+	}
+
+	t := ptrType.Elem()
+	if t.Kind() != reflect.Map {
+		return nil, reflect.Value{}, fmt.Errorf("can only get map info from maps, but got: %s", ptrType)
+	}
+
+	if v.IsNil() {
+		return nil, reflect.Value{}, fmt.Errorf("unexpected nil pointer to %v", t)
+	}
+
+	return t, v, nil
+}
@@ -0,0 +1,156 @@
+package mapi_test
+
+import (
+	"fmt"
+	"testing"
+
+	tt "github.com/vingarcia/structi/internal/testtools"
+	"github.com/vingarcia/structi/mapi"
+)
+
+func TestMapForEach(t *testing.T) {
+	t.Run("should iterate over all the items", func(t *testing.T) {
+		input := map[string]int{"a": 1, "b": 2, "c": 3}
+
+		output := map[any]any{}
+		err := mapi.ForEach(&input, func(f mapi.Field) error {
+			tt.AssertEqual(t, f.Type.String(), "int")
+			output[f.Key] = f.Value
+			return nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, map[any]any{"a": 1, "b": 2, "c": 3})
+	})
+
+	t.Run("should iterate in key order if requested", func(t *testing.T) {
+		input := map[int]string{3: "c", 1: "a", 10: "j", 2: "b"}
+
+		keys := []any{}
+		err := mapi.ForEach(&input, func(f mapi.Field) error {
+			keys = append(keys, f.Key)
+			return nil
+		}, mapi.ForEachOptions{SortKeys: true})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, keys, []any{1, 2, 3, 10})
+	})
+
+	t.Run("should group keys by kind when sorting interface keys", func(t *testing.T) {
+		input := map[any]bool{"b": true, 2: true, "a": true, 1: true}
+
+		keys := []any{}
+		err := mapi.ForEach(&input, func(f mapi.Field) error {
+			keys = append(keys, f.Key)
+			return nil
+		}, mapi.ForEachOptions{SortKeys: true})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, keys, []any{1, 2, "a", "b"})
+	})
+
+	t.Run("should allow updating and deleting items", func(t *testing.T) {
+		input := map[string]int{"a": 1, "b": 2, "c": 3}
+
+		err := mapi.ForEach(&input, func(f mapi.Field) error {
+			if f.Key == "b" {
+				f.Delete()
+				return nil
+			}
+			return f.Set(float64(f.Value.(int) * 10))
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, map[string]int{"a": 10, "c": 30})
+	})
+
+	t.Run("should report conversion errors on Set", func(t *testing.T) {
+		input := map[string]int{"a": 1}
+
+		err := mapi.ForEach(&input, func(f mapi.Field) error {
+			return f.Set("notANumber")
+		})
+		tt.AssertErrContains(t, err, "iteration error", "key 'a'", "cannot convert", "string", "int")
+	})
+
+	t.Run("should skip keys deleted during the iteration", func(t *testing.T) {
+		input := map[string]int{"a": 1, "b": 2, "c": 3}
+
+		visited := []string{}
+		err := mapi.ForEach(&input, func(f mapi.Field) error {
+			visited = append(visited, f.Key.(string))
+			delete(input, "b")
+			return nil
+		}, mapi.ForEachOptions{SortKeys: true})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, visited, []string{"a", "c"})
+	})
+
+	t.Run("should not do anything for nil maps", func(t *testing.T) {
+		var input map[string]int
+		err := mapi.ForEach(&input, func(f mapi.Field) error {
+			return fmt.Errorf("should not run")
+		})
+		tt.AssertNoErr(t, err)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		tests := []struct {
+			desc               string
+			input              any
+			expectErrToContain []string
+		}{
+			{
+				desc:               "should report error if input is not a pointer",
+				input:              map[string]int{},
+				expectErrToContain: []string{"expected map pointer", "map[string]int"},
+			},
+			{
+				desc:               "should report error if input is not a map",
+				input:              &[]int{},
+				expectErrToContain: []string{"map", "[]int"},
+			},
+			{
+				desc:               "should report error if input is nil",
+				input:              nil,
+				expectErrToContain: []string{"unexpected nil input"},
+			},
+			{
+				desc:               "should report error if input is a nil pointer",
+				input:              (*map[string]int)(nil),
+				expectErrToContain: []string{"unexpected nil pointer", "map[string]int"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.desc, func(t *testing.T) {
+				err := mapi.ForEach(test.input, func(f mapi.Field) error {
+					return nil
+				})
+				tt.AssertErrContains(t, err, test.expectErrToContain...)
+			})
+		}
+	})
+}
+
+func TestMapPut(t *testing.T) {
+	t.Run("should convert keys and values", func(t *testing.T) {
+		input := map[int]float64{}
+		err := mapi.Put(&input, int8(1), 2)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, map[int]float64{1: 2.0})
+	})
+
+	t.Run("should allocate nil maps", func(t *testing.T) {
+		var input map[string][]string
+		err := mapi.Put(&input, "key", []any{"a", "b"})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, map[string][]string{"key": {"a", "b"}})
+	})
+
+	t.Run("should report conversion errors", func(t *testing.T) {
+		var input map[string]int
+		err := mapi.Put(&input, struct{}{}, 1)
+		tt.AssertErrContains(t, err, "converting key", "struct", "string")
+		tt.AssertEqual(t, input == nil, true)
+
+		err = mapi.Put(&input, "key", "notANumber")
+		tt.AssertErrContains(t, err, "map[string]int[key]", "cannot convert", "notANumber")
+	})
+}
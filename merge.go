@@ -0,0 +1,221 @@
+package structi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeOptions allows the user to customize the behavior of Merge()
+type MergeOptions struct {
+	// OverrideWithZeroPointers makes non-nil pointers on `src`
+	// override the values on `dst` even when they point to zero values.
+	//
+	// This is useful for PATCH-like inputs where a pointer
+	// is used to tell apart "not set" from "set to zero".
+	OverrideWithZeroPointers bool
+}
+
+// These are the strategies that can be selected
+// for each field with the `merge` tag, e.g. `merge:"append"`
+const (
+	mergeDefault = ""
+	mergeReplace = "replace"
+	mergeAppend  = "append"
+	mergeDeep    = "deep"
+	mergeKeep    = "keep"
+)
+
+// Merge overlays the non-zero fields of `src` onto the struct pointed by `dst`.
+//
+// By default nested structs and maps are merged recursively and all
+// other values, including slices, are replaced. This can be changed
+// for each field with the `merge` tag:
+//
+//   - `merge:"replace"` replaces the value on `dst` as a whole
+//   - `merge:"append"` appends slices and adds the keys of maps without merging their values
+//   - `merge:"deep"` merges structs, maps and slices (item by item) recursively
+//   - `merge:"keep"` only sets the value on `dst` if it is currently empty
+//
+// The values copied from `src` are deep copies, so
+// `dst` never ends up sharing memory with `src`.
+func Merge(dst any, src any, opts ...MergeOptions) error {
+	var o MergeOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	_, dstValue, _, err := getStructInfo(dst)
+	if err != nil {
+		return err
+	}
+
+	srcValue := reflect.ValueOf(src)
+	if !srcValue.IsValid() {
+		return nil
+	}
+
+	if srcValue.Kind() == reflect.Ptr {
+		if srcValue.IsNil() {
+			return nil
+		}
+		srcValue = srcValue.Elem()
+	}
+
+	if srcValue.Type() != dstValue.Elem().Type() {
+		return fmt.Errorf("can only merge values of the same type, but got: %v and %v", dstValue.Type(), srcValue.Type())
+	}
+
+	m := merger{
+		opts:   o,
+		copier: newCopier(CopyOptions{}),
+	}
+	return m.mergeStruct("", dstValue.Elem(), srcValue)
+}
+
+type merger struct {
+	opts   MergeOptions
+	copier copier
+}
+
+func (m merger) mergeStruct(path string, dst reflect.Value, src reflect.Value) error {
+	_, fields, err := getStructInfoForType(reflect.PointerTo(dst.Type()))
+	if err != nil {
+		return err
+	}
+
+	// Structs with no exported fields like time.Time
+	// are replaced as a single value:
+	if len(fields) == 0 && dst.NumField() > 0 {
+		dst.Set(src)
+		return nil
+	}
+
+	for _, field := range fields {
+		fieldPath := joinPath(path, field.Name)
+
+		strategy := field.Tags["merge"]
+		switch strategy {
+		case mergeDefault, mergeReplace, mergeDeep, mergeKeep:
+		case mergeAppend:
+			if field.Kind != reflect.Slice && field.Kind != reflect.Map {
+				return fmt.Errorf("merge strategy '%s' can only be used on slices and maps, but field %s is of type %v", strategy, fieldPath, field.Type)
+			}
+		default:
+			return fmt.Errorf("invalid merge strategy '%s' on field %s", strategy, fieldPath)
+		}
+
+		err := m.merge(fieldPath, strategy, dst.Field(field.idx), src.Field(field.idx))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m merger) merge(path string, strategy string, dst reflect.Value, src reflect.Value) error {
+	if m.isEmpty(src) {
+		return nil
+	}
+
+	if strategy == mergeKeep && !dst.IsZero() {
+		return nil
+	}
+
+	merging := strategy == mergeDefault || strategy == mergeDeep
+	switch {
+	case merging && src.Kind() == reflect.Interface &&
+		!dst.IsNil() && dst.Elem().Type() == src.Elem().Type():
+
+		// Values stored in interfaces, e.g. on a map[string]any, are not
+		// addressable, so we merge them on a copy and then store it back:
+		value := reflect.New(dst.Elem().Type()).Elem()
+		value.Set(dst.Elem())
+		err := m.merge(path, strategy, value, src.Elem())
+		if err != nil {
+			return err
+		}
+		dst.Set(value)
+		return nil
+
+	case merging && src.Kind() == reflect.Struct:
+		return m.mergeStruct(path, dst, src)
+
+	case merging && src.Kind() == reflect.Ptr && src.Type().Elem().Kind() == reflect.Struct && !dst.IsNil():
+		return m.merge(path, strategy, dst.Elem(), src.Elem())
+
+	case (merging || strategy == mergeAppend) && src.Kind() == reflect.Map:
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), src.Len()))
+		}
+
+		iter := src.MapRange()
+		for iter.Next() {
+			value := reflect.New(dst.Type().Elem()).Elem()
+			dstValue := dst.MapIndex(iter.Key())
+			if merging && dstValue.IsValid() {
+				value.Set(dstValue)
+				err := m.merge(fmt.Sprintf("%s[%v]", path, iter.Key()), mergeDefault, value, iter.Value())
+				if err != nil {
+					return err
+				}
+			} else {
+				err := m.copier.deepCopy(value, iter.Value())
+				if err != nil {
+					return err
+				}
+			}
+			dst.SetMapIndex(iter.Key(), value)
+		}
+		return nil
+
+	case strategy == mergeAppend && src.Kind() == reflect.Slice:
+		items := reflect.New(src.Type()).Elem()
+		err := m.copier.deepCopy(items, src)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.AppendSlice(dst, items))
+		return nil
+
+	case strategy == mergeDeep && src.Kind() == reflect.Slice:
+		for i := 0; i < src.Len(); i++ {
+			if i < dst.Len() {
+				err := m.merge(fmt.Sprintf("%s[%d]", path, i), mergeDefault, dst.Index(i), src.Index(i))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			item := reflect.New(src.Type().Elem()).Elem()
+			err := m.copier.deepCopy(item, src.Index(i))
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.Append(dst, item))
+		}
+		return nil
+	}
+
+	cp := reflect.New(src.Type()).Elem()
+	err := m.copier.deepCopy(cp, src)
+	if err != nil {
+		return err
+	}
+	dst.Set(cp)
+
+	return nil
+}
+
+func (m merger) isEmpty(v reflect.Value) bool {
+	if v.IsZero() {
+		return true
+	}
+
+	if v.Kind() == reflect.Ptr && !m.opts.OverrideWithZeroPointers {
+		return m.isEmpty(v.Elem())
+	}
+
+	return false
+}
@@ -0,0 +1,220 @@
+package structi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func TestMerge(t *testing.T) {
+	type DB struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		Name      string
+		DB        DB
+		Cache     *DB
+		Hosts     []string
+		Labels    map[string]string
+		StartedAt time.Time
+	}
+
+	t.Run("should overlay non-zero fields recursively", func(t *testing.T) {
+		dst := Config{
+			Name:   "fakeName",
+			DB:     DB{Host: "localhost", Port: 5432},
+			Cache:  &DB{Host: "cache", Port: 6379},
+			Hosts:  []string{"h1", "h2"},
+			Labels: map[string]string{"env": "dev", "team": "core"},
+		}
+		startedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		err := structi.Merge(&dst, Config{
+			DB:        DB{Port: 5433},
+			Cache:     &DB{Host: "otherCache"},
+			Hosts:     []string{"h3"},
+			Labels:    map[string]string{"env": "prod"},
+			StartedAt: startedAt,
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, dst, Config{
+			Name:      "fakeName",
+			DB:        DB{Host: "localhost", Port: 5433},
+			Cache:     &DB{Host: "otherCache", Port: 6379},
+			Hosts:     []string{"h3"},
+			Labels:    map[string]string{"env": "prod", "team": "core"},
+			StartedAt: startedAt,
+		})
+	})
+
+	t.Run("should not share memory with src", func(t *testing.T) {
+		var dst Config
+		src := Config{
+			Cache: &DB{Host: "cache"},
+			Hosts: []string{"h1"},
+		}
+
+		err := structi.Merge(&dst, &src)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, dst, src)
+
+		dst.Cache.Host = "changed"
+		dst.Hosts[0] = "changed"
+		tt.AssertEqual(t, src.Cache.Host, "cache")
+		tt.AssertEqual(t, src.Hosts, []string{"h1"})
+	})
+
+	t.Run("should merge maps and structs stored in interfaces", func(t *testing.T) {
+		type M struct {
+			Cfg map[string]any
+		}
+
+		dst := M{Cfg: map[string]any{
+			"db":    map[string]any{"host": "a", "port": 1},
+			"cache": DB{Host: "cache", Port: 6379},
+			"name":  "fakeName",
+		}}
+		err := structi.Merge(&dst, M{Cfg: map[string]any{
+			"db":    map[string]any{"port": 2},
+			"cache": DB{Port: 6380},
+			"name":  42,
+		}})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, dst, M{Cfg: map[string]any{
+			"db":    map[string]any{"host": "a", "port": 2},
+			"cache": DB{Host: "cache", Port: 6380},
+			"name":  42,
+		}})
+	})
+
+	t.Run("should respect the merge strategies from the tags", func(t *testing.T) {
+		type Item struct {
+			Name  string
+			Price int
+		}
+
+		type Strategies struct {
+			Appended      []string          `merge:"append"`
+			AppendedMap   map[string]Item   `merge:"append"`
+			DeepSlice     []Item            `merge:"deep"`
+			DeepMap       map[string]Item   `merge:"deep"`
+			Replaced      DB                `merge:"replace"`
+			ReplacedMap   map[string]string `merge:"replace"`
+			Kept          string            `merge:"keep"`
+			KeptWhenEmpty string            `merge:"keep"`
+		}
+
+		dst := Strategies{
+			Appended:    []string{"a"},
+			AppendedMap: map[string]Item{"a": {Name: "a", Price: 1}},
+			DeepSlice:   []Item{{Name: "a", Price: 1}},
+			DeepMap:     map[string]Item{"a": {Name: "a", Price: 1}},
+			Replaced:    DB{Host: "localhost", Port: 5432},
+			ReplacedMap: map[string]string{"a": "a"},
+			Kept:        "original",
+		}
+		err := structi.Merge(&dst, Strategies{
+			Appended:      []string{"b"},
+			AppendedMap:   map[string]Item{"a": {Price: 2}, "b": {Name: "b"}},
+			DeepSlice:     []Item{{Price: 2}, {Name: "b"}},
+			DeepMap:       map[string]Item{"a": {Price: 2}, "b": {Name: "b"}},
+			Replaced:      DB{Port: 5433},
+			ReplacedMap:   map[string]string{"b": "b"},
+			Kept:          "new",
+			KeptWhenEmpty: "new",
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, dst, Strategies{
+			Appended:      []string{"a", "b"},
+			AppendedMap:   map[string]Item{"a": {Price: 2}, "b": {Name: "b"}},
+			DeepSlice:     []Item{{Name: "a", Price: 2}, {Name: "b"}},
+			DeepMap:       map[string]Item{"a": {Name: "a", Price: 2}, "b": {Name: "b"}},
+			Replaced:      DB{Port: 5433},
+			ReplacedMap:   map[string]string{"b": "b"},
+			Kept:          "original",
+			KeptWhenEmpty: "new",
+		})
+	})
+
+	t.Run("pointers to zero values", func(t *testing.T) {
+		type Patch struct {
+			Name   *string
+			Active *bool
+		}
+
+		name := "fakeName"
+		active := true
+		emptyName := ""
+		inactive := false
+
+		t.Run("should be ignored by default", func(t *testing.T) {
+			dst := Patch{Name: &name, Active: &active}
+			err := structi.Merge(&dst, Patch{Name: &emptyName, Active: &inactive})
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, *dst.Name, "fakeName")
+			tt.AssertEqual(t, *dst.Active, true)
+		})
+
+		t.Run("should override values if OverrideWithZeroPointers is set", func(t *testing.T) {
+			dst := Patch{Name: &name, Active: &active}
+			err := structi.Merge(&dst, Patch{Name: &emptyName, Active: &inactive}, structi.MergeOptions{
+				OverrideWithZeroPointers: true,
+			})
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, *dst.Name, "")
+			tt.AssertEqual(t, *dst.Active, false)
+		})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		tests := []struct {
+			desc               string
+			dst                any
+			src                any
+			expectErrToContain []string
+		}{
+			{
+				desc:               "dst is not a pointer",
+				dst:                Config{},
+				src:                Config{},
+				expectErrToContain: []string{"expected struct pointer"},
+			},
+			{
+				desc:               "different types",
+				dst:                &Config{},
+				src:                DB{},
+				expectErrToContain: []string{"same type", "Config", "DB"},
+			},
+			{
+				desc: "invalid strategy",
+				dst: &struct {
+					Attr1 string `merge:"invalid"`
+				}{},
+				src: struct {
+					Attr1 string `merge:"invalid"`
+				}{},
+				expectErrToContain: []string{"invalid merge strategy", "invalid", "Attr1"},
+			},
+			{
+				desc: "append strategy on a non-slice",
+				dst: &struct {
+					Attr1 string `merge:"append"`
+				}{},
+				src: struct {
+					Attr1 string `merge:"append"`
+				}{},
+				expectErrToContain: []string{"append", "Attr1", "string"},
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.desc, func(t *testing.T) {
+				err := structi.Merge(test.dst, test.src)
+				tt.AssertErrContains(t, err, test.expectErrToContain...)
+			})
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package structi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NestedOptions allows the user to customize the behavior of Field.ForEachNested()
+type NestedOptions struct {
+	// ResetIfEmpty sets pointer fields back to nil if the nested
+	// struct is left with its zero value after the iteration, so
+	// that sparse inputs don't produce empty structs.
+	ResetIfEmpty bool
+}
+
+// Alloc allocates the value pointed by the field if it is a nil pointer,
+// including all the pointers in between for pointer to pointer fields,
+// and returns the innermost pointer, e.g. a `**Address` field returns
+// an `*Address`.
+//
+// For non-pointer fields it just returns the Field.Value.
+func (f Field) Alloc() (any, error) {
+	v := reflect.ValueOf(f.Value)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, fmt.Errorf("cannot allocate field '%s': expected Field.Value to be a non-nil pointer but got: %#v", f.Name, f.Value)
+	}
+
+	for v.Elem().Kind() == reflect.Ptr {
+		v = v.Elem()
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+	}
+
+	return v.Interface(), nil
+}
+
+// ForEachNested iterates over the attributes of a struct field, or pointer
+// to struct field, calling the `iterate` function for each attribute.
+//
+// Nil pointer fields are only allocated if the nested struct is modified
+// during the iteration, so the field is kept nil if nothing was set.
+func (f Field) ForEachNested(iterate IteratorFunc, opts ...NestedOptions) error {
+	var o NestedOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	fieldValue := reflect.ValueOf(f.Value)
+	if fieldValue.Kind() != reflect.Ptr || fieldValue.IsNil() {
+		return fmt.Errorf("cannot iterate over field '%s': expected Field.Value to be a non-nil pointer but got: %#v", f.Name, f.Value)
+	}
+	fieldValue = fieldValue.Elem()
+
+	structType := fieldValue.Type()
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot iterate over field '%s': expected struct or pointer to struct but got: %v", f.Name, fieldValue.Type())
+	}
+
+	// Reuse the existing struct if all the pointers are non-nil:
+	target := fieldValue
+	for target.Kind() == reflect.Ptr && !target.IsNil() {
+		target = target.Elem()
+	}
+
+	isAllocated := target.Kind() == reflect.Struct
+	if isAllocated {
+		target = target.Addr()
+	} else {
+		target = reflect.New(structType)
+	}
+
+	written := false
+	err := ForEach(target, func(field Field) error {
+		set := field.Set
+		field.Set = func(value any) error {
+			written = true
+			return set(value)
+		}
+		return iterate(field)
+	})
+	if err != nil {
+		return err
+	}
+
+	isEmpty := target.Elem().IsZero()
+	if fieldValue.Kind() == reflect.Ptr && o.ResetIfEmpty && isEmpty {
+		fieldValue.Set(reflect.Zero(fieldValue.Type()))
+		return nil
+	}
+
+	if !isAllocated && (written || !isEmpty) {
+		setPointerChain(fieldValue, target)
+	}
+
+	return nil
+}
+
+// setPointerChain makes `v` point to `target` allocating
+// the pointers in between if necessary.
+func setPointerChain(v reflect.Value, target reflect.Value) {
+	for v.Type() != target.Type() {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	v.Set(target)
+}
@@ -0,0 +1,178 @@
+package structi_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+type nestedAddress struct {
+	Street string `env:"street"`
+	City   string `env:"city"`
+}
+
+func TestFieldAlloc(t *testing.T) {
+	t.Run("should allocate nil pointers", func(t *testing.T) {
+		var output struct {
+			Address    *nestedAddress
+			PtrToPtr   **nestedAddress
+			Value      nestedAddress
+			PtrToValue *int
+		}
+
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			ptr, err := field.Alloc()
+			if err != nil {
+				return err
+			}
+
+			if address, ok := ptr.(*nestedAddress); ok {
+				address.City = field.Name
+				return nil
+			}
+
+			*ptr.(*int) = 42
+			return nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Address, &nestedAddress{City: "Address"})
+		tt.AssertEqual(t, *output.PtrToPtr, &nestedAddress{City: "PtrToPtr"})
+		tt.AssertEqual(t, output.Value, nestedAddress{City: "Value"})
+		tt.AssertEqual(t, *output.PtrToValue, 42)
+	})
+
+	t.Run("should keep pointers that are already allocated", func(t *testing.T) {
+		address := &nestedAddress{Street: "fakeStreet"}
+		output := struct {
+			Address *nestedAddress
+		}{
+			Address: address,
+		}
+
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			ptr, err := field.Alloc()
+			tt.AssertEqual(t, ptr, address)
+			return err
+		})
+		tt.AssertNoErr(t, err)
+	})
+}
+
+func TestFieldForEachNested(t *testing.T) {
+	t.Run("should allocate nil pointer structs when fields are set", func(t *testing.T) {
+		var output struct {
+			Attr1    int
+			Address  *nestedAddress
+			PtrToPtr **nestedAddress
+		}
+
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			if field.Kind != reflect.Ptr {
+				return field.Set(64)
+			}
+
+			return field.ForEachNested(func(field structi.Field) error {
+				return field.Set("fake-" + field.Tags["env"])
+			})
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, 64)
+		tt.AssertEqual(t, output.Address, &nestedAddress{Street: "fake-street", City: "fake-city"})
+		tt.AssertEqual(t, *output.PtrToPtr, &nestedAddress{Street: "fake-street", City: "fake-city"})
+	})
+
+	t.Run("should keep nil pointers if nothing is set", func(t *testing.T) {
+		var output struct {
+			Address *nestedAddress
+		}
+
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			return field.ForEachNested(func(field structi.Field) error {
+				return nil
+			})
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Address == nil, true)
+	})
+
+	t.Run("should allocate pointers even if only zero values are set", func(t *testing.T) {
+		var output struct {
+			Address *nestedAddress
+		}
+
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			return field.ForEachNested(func(field structi.Field) error {
+				return field.Set("")
+			})
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Address, &nestedAddress{})
+	})
+
+	t.Run("should update existing structs in place", func(t *testing.T) {
+		address := &nestedAddress{Street: "fakeStreet"}
+		output := struct {
+			Address *nestedAddress
+			Value   nestedAddress
+		}{
+			Address: address,
+		}
+
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			return field.ForEachNested(func(field structi.Field) error {
+				if field.Name == "City" {
+					return field.Set("fakeCity")
+				}
+				return nil
+			})
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Address == address, true)
+		tt.AssertEqual(t, address, &nestedAddress{Street: "fakeStreet", City: "fakeCity"})
+		tt.AssertEqual(t, output.Value, nestedAddress{City: "fakeCity"})
+	})
+
+	t.Run("should reset empty structs to nil if requested", func(t *testing.T) {
+		output := struct {
+			Address *nestedAddress
+		}{
+			Address: &nestedAddress{Street: "fakeStreet"},
+		}
+
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			return field.ForEachNested(func(field structi.Field) error {
+				return field.Set("")
+			}, structi.NestedOptions{ResetIfEmpty: true})
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Address == nil, true)
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		var output struct {
+			Attr1   *int
+			Address *nestedAddress
+		}
+
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			return field.ForEachNested(func(field structi.Field) error {
+				return nil
+			})
+		})
+		tt.AssertErrContains(t, err, "Attr1", "expected struct or pointer to struct", "*int")
+
+		err = structi.ForEach(&output, func(field structi.Field) error {
+			if field.Name != "Address" {
+				return nil
+			}
+			return field.ForEachNested(func(field structi.Field) error {
+				return fmt.Errorf("fakeErrMsg")
+			})
+		})
+		tt.AssertErrContains(t, err, "Address", "Street", "fakeErrMsg")
+		tt.AssertEqual(t, output.Address == nil, true)
+	})
+}
@@ -0,0 +1,93 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vingarcia/structi/schema"
+)
+
+// Components represents the `components` section of an OpenAPI 3.1 document.
+type Components struct {
+	Schemas map[string]*schema.Schema `json:"schemas"`
+}
+
+// Options allows the user to customize the behavior of GenerateComponents()
+type Options struct {
+	// Tag is the tag used for reading the property names
+	// and the `omitempty` option, "json" by default.
+	Tag string
+
+	// ValidationTag is the tag used for reading the `required`,
+	// `min`, `max` and `oneof` rules, "validate" by default.
+	ValidationTag string
+}
+
+// GenerateComponents builds the `components.schemas` entries
+// for the input types, which should all be named struct types.
+//
+// The schemas follow the same rules described on the schema subpackage,
+// and on top of that pointers are nullable, examples are read from the
+// `example` tag and nested named structs are reused with `$ref`.
+func GenerateComponents(types []reflect.Type, opts ...Options) (*Components, error) {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	g := schema.NewGenerator(schema.Options{
+		Tag:              o.Tag,
+		ValidationTag:    o.ValidationTag,
+		RefPrefix:        "#/components/schemas/",
+		NullablePointers: true,
+	})
+	for _, t := range types {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t.Name() == "" {
+			return nil, fmt.Errorf("can only generate components for named struct types, but got: %v", t)
+		}
+
+		_, err := g.Schema(t)
+		if err != nil {
+			return nil, fmt.Errorf("error generating component for type %v: %w", t, err)
+		}
+	}
+
+	return &Components{
+		Schemas: g.Defs(),
+	}, nil
+}
+
+// JSON encodes the components as an indented JSON document
+// with a single `components` key, ready to be merged into
+// the rest of the OpenAPI document.
+func (c Components) JSON() ([]byte, error) {
+	return json.MarshalIndent(map[string]any{
+		"components": c,
+	}, "", "  ")
+}
+
+// YAML encodes the components as a YAML document
+// with a single `components` key, ready to be merged into
+// the rest of the OpenAPI document.
+func (c Components) YAML() ([]byte, error) {
+	b, err := c.JSON()
+	if err != nil {
+		return nil, err
+	}
+
+	// The schema types only have `json` tags, so we go through
+	// a generic value in order to reuse them for the YAML output:
+	var doc any
+	err = json.Unmarshal(b, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(doc)
+}
@@ -0,0 +1,138 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tt "github.com/vingarcia/structi/internal/testtools"
+	"github.com/vingarcia/structi/openapi"
+)
+
+type UUID [16]byte
+
+type Address struct {
+	City string `json:"city" example:"Berlin"`
+}
+
+type CreateUserRequest struct {
+	ID        UUID      `json:"id"`
+	Name      string    `json:"name" description:"The user name" example:"John"`
+	Age       *int      `json:"age,omitempty" example:"42"`
+	Avatar    []byte    `json:"avatar,omitempty"`
+	Address   *Address  `json:"address,omitempty" description:"The home address"`
+	Addresses []Address `json:"addresses,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserResponse struct {
+	Name    string  `json:"name"`
+	Address Address `json:"address"`
+}
+
+func TestGenerateComponents(t *testing.T) {
+	t.Run("should generate components for the input types", func(t *testing.T) {
+		c, err := openapi.GenerateComponents([]reflect.Type{
+			reflect.TypeOf(CreateUserRequest{}),
+			reflect.TypeOf(&UserResponse{}),
+		})
+		tt.AssertNoErr(t, err)
+
+		b, err := c.JSON()
+		tt.AssertNoErr(t, err)
+
+		var got, expected any
+		tt.AssertNoErr(t, json.Unmarshal(b, &got))
+		tt.AssertNoErr(t, json.Unmarshal([]byte(`{
+			"components": {
+				"schemas": {
+					"Address": {
+						"type": "object",
+						"properties": {
+							"city": {"type": "string", "examples": ["Berlin"]}
+						},
+						"required": ["city"]
+					},
+					"CreateUserRequest": {
+						"type": "object",
+						"properties": {
+							"id": {"type": "string", "format": "uuid"},
+							"name": {"type": "string", "description": "The user name", "examples": ["John"]},
+							"age": {"type": ["integer", "null"], "examples": [42]},
+							"avatar": {"type": "string", "contentEncoding": "base64"},
+							"address": {
+								"description": "The home address",
+								"anyOf": [
+									{"$ref": "#/components/schemas/Address"},
+									{"type": "null"}
+								]
+							},
+							"addresses": {
+								"type": "array",
+								"items": {"$ref": "#/components/schemas/Address"}
+							},
+							"created_at": {"type": "string", "format": "date-time"}
+						},
+						"required": ["id", "name", "created_at"]
+					},
+					"UserResponse": {
+						"type": "object",
+						"properties": {
+							"name": {"type": "string"},
+							"address": {"$ref": "#/components/schemas/Address"}
+						},
+						"required": ["name", "address"]
+					}
+				}
+			}
+		}`), &expected))
+		tt.AssertEqual(t, got, expected)
+	})
+
+	t.Run("should encode the components as YAML", func(t *testing.T) {
+		c, err := openapi.GenerateComponents([]reflect.Type{
+			reflect.TypeOf(UserResponse{}),
+		})
+		tt.AssertNoErr(t, err)
+
+		b, err := c.YAML()
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, strings.TrimSpace(string(b)), strings.TrimSpace(`
+components:
+    schemas:
+        Address:
+            properties:
+                city:
+                    examples:
+                        - Berlin
+                    type: string
+            required:
+                - city
+            type: object
+        UserResponse:
+            properties:
+                address:
+                    $ref: '#/components/schemas/Address'
+                name:
+                    type: string
+            required:
+                - name
+                - address
+            type: object
+`))
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		_, err := openapi.GenerateComponents([]reflect.Type{
+			reflect.TypeOf(struct{ Name string }{}),
+		})
+		tt.AssertErrContains(t, err, "named struct types", "Name string")
+
+		_, err = openapi.GenerateComponents([]reflect.Type{
+			reflect.TypeOf(42),
+		})
+		tt.AssertErrContains(t, err, "named struct types", "int")
+	})
+}
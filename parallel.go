@@ -0,0 +1,125 @@
+package structi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ParallelOptions allows the user to customize the behavior of ForEachParallel()
+type ParallelOptions struct {
+	// MaxWorkers limits the number of fields processed at the same
+	// time, if it is zero all the fields are processed concurrently.
+	MaxWorkers int
+}
+
+// ForEachParallel works like ForEach but calls the `iterate` function
+// concurrently for each field, which is useful when each field is
+// loaded by a slow lookup, e.g. on a remote secrets manager.
+//
+// It accepts the same inputs as ForEach, and calling Set() on different
+// fields from different goroutines is safe, but the `iterate` function
+// must synchronize any other state it shares between the fields.
+//
+// All the fields are processed even if some of them fail and the
+// returned error joins the errors of each field in the order the
+// fields are declared on the struct. Panics on the `iterate` function
+// are recovered and reported as the error of the respective field.
+func ForEachParallel(targetStruct any, iterate IteratorFunc, opts ...ParallelOptions) error {
+	var o ParallelOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	ptr, ok := targetStruct.(reflect.Value)
+	if !ok {
+		ptr = reflect.ValueOf(targetStruct)
+	}
+	if ptr.Kind() == reflect.Ptr && !ptr.IsNil() && ptr.Elem().Kind() == reflect.Interface {
+		return forEachInterface(ptr, func(structPtr reflect.Value) error {
+			return ForEachParallel(structPtr, iterate, o)
+		})
+	}
+
+	// Using ForEach for collecting the fields makes sure we
+	// also use the generated iterators when they are available:
+	fields := []Field{}
+	err := ForEach(targetStruct, func(field Field) error {
+		info := *field.fieldInfo
+		field.fieldInfo = &info
+		fields = append(fields, field)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	maxWorkers := o.MaxWorkers
+	if maxWorkers <= 0 || maxWorkers > len(fields) {
+		maxWorkers = len(fields)
+	}
+
+	errs := make([]error, len(fields))
+	workers := make(chan struct{}, maxWorkers)
+	var wg sync.WaitGroup
+	for i := range fields {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			field := fields[i]
+			err := callIterator(iterate, field)
+			if err != nil {
+				errs[i] = fmt.Errorf("iteration error on field '%s' of type '%v': %w", field.Name, field.Type, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return joinErrors(errs)
+}
+
+// callIterator converts panics into errors since they
+// can't be recovered by the caller of ForEachParallel()
+func callIterator(iterate IteratorFunc, field Field) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return iterate(field)
+}
+
+// parallelErrors keeps the errors of each field in order
+// and allows unwrapping them with errors.Is() and errors.As()
+type parallelErrors []error
+
+func (e parallelErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e parallelErrors) Unwrap() []error {
+	return e
+}
+
+func joinErrors(errs []error) error {
+	var nonNil parallelErrors
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+
+	if len(nonNil) == 0 {
+		return nil
+	}
+	return nonNil
+}
@@ -0,0 +1,146 @@
+package structi_test
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func TestForEachParallel(t *testing.T) {
+	t.Run("should set all fields concurrently", func(t *testing.T) {
+		var output struct {
+			Attr1 int
+			Attr2 string
+			Attr3 float64
+			Attr4 *int
+			Attr5 []int
+		}
+
+		values := map[string]any{
+			"Attr1": 1,
+			"Attr2": "2",
+			"Attr3": 3,
+			"Attr4": 4,
+			"Attr5": []any{5},
+		}
+		err := structi.ForEachParallel(&output, func(field structi.Field) error {
+			return field.Set(values[field.Name])
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, 1)
+		tt.AssertEqual(t, output.Attr2, "2")
+		tt.AssertEqual(t, output.Attr3, 3.0)
+		tt.AssertEqual(t, *output.Attr4, 4)
+		tt.AssertEqual(t, output.Attr5, []int{5})
+	})
+
+	t.Run("should respect the max number of workers", func(t *testing.T) {
+		var output struct {
+			Attr1 int
+			Attr2 int
+			Attr3 int
+			Attr4 int
+			Attr5 int
+		}
+
+		var running, maxRunning int32
+		err := structi.ForEachParallel(&output, func(field structi.Field) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			for {
+				current := atomic.LoadInt32(&maxRunning)
+				if n <= current || atomic.CompareAndSwapInt32(&maxRunning, current, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			return field.Set(n)
+		}, structi.ParallelOptions{MaxWorkers: 2})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, atomic.LoadInt32(&maxRunning) <= 2, true)
+	})
+
+	t.Run("should report errors in the order of the fields", func(t *testing.T) {
+		var output struct {
+			Attr1 int
+			Attr2 int
+			Attr3 int
+		}
+
+		err := structi.ForEachParallel(&output, func(field structi.Field) error {
+			if field.Name == "Attr2" {
+				return field.Set(1)
+			}
+
+			// Making the first field fail last:
+			if field.Name == "Attr1" {
+				time.Sleep(10 * time.Millisecond)
+			}
+			return fmt.Errorf("fakeErrMsg%s", field.Name)
+		})
+		tt.AssertEqual(t, err.Error(), "iteration error on field 'Attr1' of type 'int': fakeErrMsgAttr1\n"+
+			"iteration error on field 'Attr3' of type 'int': fakeErrMsgAttr3")
+		tt.AssertEqual(t, output.Attr2, 1)
+	})
+
+	t.Run("should allow unwrapping the errors of each field", func(t *testing.T) {
+		var output struct {
+			Attr1 int
+		}
+
+		fakeErr := errors.New("fakeErrMsg")
+		err := structi.ForEachParallel(&output, func(field structi.Field) error {
+			return fakeErr
+		})
+		tt.AssertEqual(t, errors.Is(err, fakeErr), true)
+	})
+
+	t.Run("should report panics as errors of the respective field", func(t *testing.T) {
+		var output struct {
+			Attr1 int
+			Attr2 int
+		}
+
+		err := structi.ForEachParallel(&output, func(field structi.Field) error {
+			if field.Name == "Attr2" {
+				panic("fakePanicMsg")
+			}
+			return field.Set(1)
+		})
+		tt.AssertErrContains(t, err, "Attr2", "panic", "fakePanicMsg")
+		tt.AssertEqual(t, output.Attr1, 1)
+	})
+
+	t.Run("should accept the same inputs as ForEach", func(t *testing.T) {
+		var shape Shape = Rect{}
+		err := structi.ForEachParallel(&shape, func(field structi.Field) error {
+			return field.Set(2)
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, shape, Shape(Rect{Width: 2, Height: 2}))
+
+		var s generatedStruct
+		err = structi.ForEachParallel(&s, func(field structi.Field) error {
+			if field.Name == "Name" {
+				return field.Set("fakeName")
+			}
+			return nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, s.Name, "fakeName (generated)")
+	})
+
+	t.Run("should report errors for invalid inputs", func(t *testing.T) {
+		err := structi.ForEachParallel(&[]int{}, func(field structi.Field) error {
+			return nil
+		})
+		tt.AssertErrContains(t, err, "can only get struct info from structs", "[]int")
+	})
+}
@@ -0,0 +1,295 @@
+package structi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"regexp"
+)
+
+// DefaultSecretNames is the pattern used by Redact() for detecting secret
+// fields and map keys by their names when no other pattern is informed.
+var DefaultSecretNames = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|credential|private_?key)`)
+
+// RedactOptions allows the user to customize the behavior of Redact()
+type RedactOptions struct {
+	// Mask replaces the values of the secret fields,
+	// it is "[REDACTED]" by default.
+	Mask string
+
+	// SecretNames is used for detecting secret fields and map keys by
+	// their names, besides the fields tagged with `secret:"true"`.
+	//
+	// It is DefaultSecretNames by default, set it to a pattern that
+	// matches no names, e.g. `^$`, for disabling it.
+	SecretNames *regexp.Regexp
+
+	// Tag is used for reading the keys of the output maps, e.g. "json",
+	// if empty or if the field has no such tag the field name is used.
+	Tag string
+}
+
+// Redact converts the input struct (or pointer to struct) into a map
+// replacing the values of the secret fields with a mask, recursing
+// into nested structs, slices and maps.
+//
+// Secret fields are the ones tagged with `secret:"true"` or whose
+// names match the RedactOptions.SecretNames pattern, the map keys
+// matching this pattern are also redacted.
+func Redact(targetStruct any, opts ...RedactOptions) (map[string]any, error) {
+	r := newRedactor(opts)
+
+	v := reflect.ValueOf(targetStruct)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		// Mark the root as visited so that fields pointing
+		// back to it are also detected as cycles:
+		r.visiting[visitKey{ptr: v.Pointer(), t: v.Type()}] = true
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can only redact structs, but got: %T", targetStruct)
+	}
+
+	output := map[string]any{}
+	err := r.redactStruct(output, v)
+	return output, err
+}
+
+// Redacted wraps a struct so that it can be safely
+// printed or logged without leaking its secret fields.
+type Redacted struct {
+	value any
+	opts  RedactOptions
+}
+
+// NewRedacted wraps the input struct (or pointer to struct)
+// in a Redacted instance, check Redact() for details.
+//
+// Just like on LogValue() the keys are read from the `log` tag by default,
+// so that the struct is rendered with the same keys when printed and logged.
+func NewRedacted(targetStruct any, opts ...RedactOptions) Redacted {
+	var o RedactOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if o.Tag == "" {
+		o.Tag = "log"
+	}
+
+	return Redacted{
+		value: targetStruct,
+		opts:  o,
+	}
+}
+
+// String implements the fmt.Stringer interface
+// formatting the redacted struct as JSON.
+func (r Redacted) String() string {
+	m, err := Redact(r.value, r.opts)
+	if err != nil {
+		return fmt.Sprintf("!REDACT_ERROR(%s)", err)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Sprintf("!REDACT_ERROR(%s)", err)
+	}
+
+	return string(b)
+}
+
+// GoString implements the fmt.GoStringer interface so that
+// the `%#v` verb also prints the redacted struct.
+func (r Redacted) GoString() string {
+	return r.String()
+}
+
+// Format implements the fmt.Formatter interface so that the
+// wrapped struct is never printed by fmt using reflection,
+// which would expose its secrets for verbs like `%d` or `%#v`.
+func (r Redacted) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'q':
+		fmt.Fprintf(f, "%q", r.String())
+	case verb == 'v' && f.Flag('#'):
+		io.WriteString(f, r.GoString())
+	default:
+		io.WriteString(f, r.String())
+	}
+}
+
+// LogValue implements the slog.LogValuer interface
+// by calling structi.LogValue() on the wrapped struct.
+func (r Redacted) LogValue() slog.Value {
+	return LogValue(r.value, r.opts)
+}
+
+type redactor struct {
+	opts     RedactOptions
+	visiting map[visitKey]bool
+}
+
+func newRedactor(opts []RedactOptions) redactor {
+	var o RedactOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if o.Mask == "" {
+		o.Mask = "[REDACTED]"
+	}
+
+	if o.SecretNames == nil {
+		o.SecretNames = DefaultSecretNames
+	}
+
+	return redactor{
+		opts:     o,
+		visiting: map[visitKey]bool{},
+	}
+}
+
+func (r redactor) redactStruct(output map[string]any, v reflect.Value) error {
+	_, fields, err := getStructInfoForType(reflect.PointerTo(v.Type()))
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		fieldValue := v.Field(field.idx)
+
+		key, tagName := fieldKey(field, r.opts.Tag)
+		if tagName == "-" {
+			continue
+		}
+
+		if r.isSecret(field) {
+			output[key] = r.opts.Mask
+			continue
+		}
+
+		// Embedded structs have their fields promoted like on encoding/json:
+		if elem, ok := promotedStruct(field, tagName, fieldValue); ok {
+			err := r.redactStruct(output, elem)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := r.redactValue(fieldValue)
+		if err != nil {
+			return fmt.Errorf("error redacting field '%s': %w", field.Name, err)
+		}
+		output[key] = value
+	}
+
+	return nil
+}
+
+func (r redactor) isSecret(field fieldInfo) bool {
+	return field.Tags["secret"] == "true" || r.opts.SecretNames.MatchString(field.Name)
+}
+
+// promotedStruct returns the struct stored on an embedded field
+// if its fields should be promoted to the parent struct.
+func promotedStruct(field fieldInfo, tagName string, fieldValue reflect.Value) (reflect.Value, bool) {
+	if !field.IsEmbeded || tagName != "" {
+		return reflect.Value{}, false
+	}
+
+	for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+		fieldValue = fieldValue.Elem()
+	}
+
+	return fieldValue, fieldValue.Kind() == reflect.Struct
+}
+
+func (r redactor) redactValue(v reflect.Value) (any, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		key := visitKey{ptr: v.Pointer(), t: v.Type()}
+		if r.visiting[key] {
+			return fmt.Sprintf("<cycle %v>", v.Type()), nil
+		}
+		r.visiting[key] = true
+		defer delete(r.visiting, key)
+
+		return r.redactValue(v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return r.redactValue(v.Elem())
+
+	case reflect.Struct:
+		if isOpaqueStruct(v.Type()) {
+			return v.Interface(), nil
+		}
+
+		output := map[string]any{}
+		err := r.redactStruct(output, v)
+		return output, err
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface(), nil
+		}
+
+		items := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := r.redactValue(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("error redacting item %d: %w", i, err)
+			}
+			items[i] = item
+		}
+		return items, nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		output := map[string]any{}
+		for _, key := range sortedMapKeys(v) {
+			keyStr := fmt.Sprint(key)
+			if r.opts.SecretNames.MatchString(keyStr) {
+				output[keyStr] = r.opts.Mask
+				continue
+			}
+
+			value, err := r.redactValue(v.MapIndex(key))
+			if err != nil {
+				return nil, fmt.Errorf("error redacting key '%s': %w", keyStr, err)
+			}
+			output[keyStr] = value
+		}
+		return output, nil
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil, fmt.Errorf("cannot redact values of type %v", v.Type())
+	}
+
+	return v.Interface(), nil
+}
+
+// isOpaqueStruct checks if the struct has no exported fields, e.g.
+// time.Time, in which case it should be handled as a single value.
+func isOpaqueStruct(t reflect.Type) bool {
+	_, fields, err := getStructInfoForType(reflect.PointerTo(t))
+	return err == nil && len(fields) == 0 && t.NumField() > 0
+}
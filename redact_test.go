@@ -0,0 +1,214 @@
+package structi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+func TestRedact(t *testing.T) {
+	type Database struct {
+		Host     string
+		Password string
+		Key      string `secret:"true"`
+	}
+
+	t.Run("should mask secret fields recursively", func(t *testing.T) {
+		input := struct {
+			Name      string
+			APIToken  string
+			DB        Database
+			Replicas  []*Database
+			Headers   map[string]string
+			CreatedAt time.Time
+			unexposed string
+		}{
+			Name:     "app",
+			APIToken: "fakeToken",
+			DB:       Database{Host: "localhost", Password: "fakePass", Key: "fakeKey"},
+			Replicas: []*Database{{Host: "replica", Password: "fakePass"}, nil},
+			Headers: map[string]string{
+				"Accept":        "text/plain",
+				"X-Auth-Token":  "fakeToken",
+				"client_secret": "fakeSecret",
+			},
+			CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			unexposed: "foo",
+		}
+
+		output, err := structi.Redact(&input)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, map[string]any{
+			"Name":     "app",
+			"APIToken": "[REDACTED]",
+			"DB": map[string]any{
+				"Host":     "localhost",
+				"Password": "[REDACTED]",
+				"Key":      "[REDACTED]",
+			},
+			"Replicas": []any{
+				map[string]any{
+					"Host":     "replica",
+					"Password": "[REDACTED]",
+					"Key":      "[REDACTED]",
+				},
+				nil,
+			},
+			"Headers": map[string]any{
+				"Accept":        "text/plain",
+				"X-Auth-Token":  "[REDACTED]",
+				"client_secret": "[REDACTED]",
+			},
+			"CreatedAt": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		})
+
+		// The input should not be changed:
+		tt.AssertEqual(t, input.DB.Password, "fakePass")
+	})
+
+	t.Run("should use the options correctly", func(t *testing.T) {
+		input := struct {
+			Name     string `json:"name"`
+			Password string `json:"password"`
+			Internal string `json:"-"`
+			Pin      int    `json:"pin,omitempty"`
+		}{
+			Name:     "foo",
+			Password: "fakePass",
+			Internal: "bar",
+			Pin:      1234,
+		}
+
+		output, err := structi.Redact(input, structi.RedactOptions{
+			Mask:        "***",
+			SecretNames: regexp.MustCompile(`^(Password|Pin)$`),
+			Tag:         "json",
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, map[string]any{
+			"name":     "foo",
+			"password": "***",
+			"pin":      "***",
+		})
+	})
+
+	t.Run("should promote the fields of embedded structs", func(t *testing.T) {
+		type Login struct {
+			User   string
+			Secret string
+		}
+		input := struct {
+			*Login
+			Name string
+		}{
+			Login: &Login{User: "foo", Secret: "fakeSecret"},
+			Name:  "bar",
+		}
+
+		output, err := structi.Redact(&input)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, map[string]any{
+			"User":   "foo",
+			"Secret": "[REDACTED]",
+			"Name":   "bar",
+		})
+	})
+
+	t.Run("should not loop forever on cycles", func(t *testing.T) {
+		root := &walkNode{Name: "root"}
+		root.Children = []*walkNode{{Name: "child", Parent: root}}
+
+		output, err := structi.Redact(root)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, map[string]any{
+			"Name": "root",
+			"Children": []any{
+				map[string]any{
+					"Name":     "child",
+					"Children": nil,
+					"Parent":   "<cycle *structi_test.walkNode>",
+				},
+			},
+			"Parent": nil,
+		})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		_, err := structi.Redact(42)
+		tt.AssertErrContains(t, err, "can only redact structs", "int")
+
+		_, err = structi.Redact(struct {
+			Callback func()
+		}{})
+		tt.AssertErrContains(t, err, "Callback", "func()")
+	})
+}
+
+func TestRedacted(t *testing.T) {
+	type Config struct {
+		User     string
+		Password string
+	}
+	config := Config{User: "foo", Password: "fakePass"}
+
+	t.Run("should format the struct as JSON without the secrets", func(t *testing.T) {
+		output := fmt.Sprint(structi.NewRedacted(&config))
+		tt.AssertEqual(t, output, `{"Password":"[REDACTED]","User":"foo"}`)
+
+		output = fmt.Sprint(structi.NewRedacted(42))
+		tt.AssertTrue(t, strings.HasPrefix(output, "!REDACT_ERROR("), output)
+	})
+
+	t.Run("should not leak the secrets with any formatting verb", func(t *testing.T) {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%d", "%x"} {
+			output := fmt.Sprintf(format, structi.NewRedacted(config))
+			tt.AssertTrue(t, !strings.Contains(output, "fakePass"), format, output)
+			tt.AssertTrue(t, strings.Contains(output, "[REDACTED]"), format, output)
+		}
+	})
+
+	t.Run("should use the same keys when printing and logging", func(t *testing.T) {
+		type Login struct {
+			User     string `log:"user"`
+			Password string `log:"password"`
+			Internal string `log:"-"`
+		}
+		login := Login{User: "foo", Password: "fakePass", Internal: "fakeInternal"}
+
+		tt.AssertEqual(t, fmt.Sprint(structi.NewRedacted(login)), `{"password":"[REDACTED]","user":"foo"}`)
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		logger.Info("starting", "login", structi.NewRedacted(login))
+
+		var entry map[string]any
+		err := json.Unmarshal(buf.Bytes(), &entry)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, entry["login"], map[string]any{
+			"user":     "foo",
+			"password": "[REDACTED]",
+		})
+	})
+
+	t.Run("should log the struct without the secrets", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		logger.Info("starting", "config", structi.NewRedacted(config))
+
+		var entry map[string]any
+		err := json.Unmarshal(buf.Bytes(), &entry)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, entry["config"], map[string]any{
+			"User":     "foo",
+			"Password": "[REDACTED]",
+		})
+	})
+}
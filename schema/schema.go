@@ -0,0 +1,450 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vingarcia/structi"
+	"github.com/vingarcia/structi/internal/types"
+	"github.com/vingarcia/structi/tags"
+)
+
+// Draft is the JSON Schema dialect produced by this package
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema represents a JSON Schema document or subschema.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Description string `json:"description,omitempty"`
+	Examples    []any  `json:"examples,omitempty"`
+
+	Type            TypeList `json:"type,omitempty"`
+	Format          string   `json:"format,omitempty"`
+	ContentEncoding string   `json:"contentEncoding,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+
+	Enum      []any    `json:"enum,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	MinItems  *int     `json:"minItems,omitempty"`
+	MaxItems  *int     `json:"maxItems,omitempty"`
+
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// TypeList represents the `type` keyword, which can either
+// be a single type name or a list of type names.
+type TypeList []string
+
+// MarshalJSON implements the json.Marshaler interface
+func (t TypeList) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (t *TypeList) UnmarshalJSON(b []byte) error {
+	var name string
+	if json.Unmarshal(b, &name) == nil {
+		*t = TypeList{name}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(t))
+}
+
+// Options allows the user to customize the behavior of Generate() and NewGenerator()
+type Options struct {
+	// Tag is the tag used for reading the property names
+	// and the `omitempty` option, "json" by default.
+	Tag string
+
+	// ValidationTag is the tag used for reading the `required`,
+	// `min`, `max` and `oneof` rules, "validate" by default.
+	ValidationTag string
+
+	// RefPrefix is the prefix used for the `$ref` keywords
+	// pointing to named types, "#/$defs/" by default.
+	RefPrefix string
+
+	// NullablePointers makes pointer fields accept `null` values.
+	NullablePointers bool
+}
+
+// Generate builds a JSON Schema for the input type, which should
+// be a struct or a pointer to a struct.
+//
+// The properties are named after the `json` tags and descriptions are
+// read from the `description` tag. Fields are required unless they have
+// the `omitempty` option, and the `required` validation rule makes a field
+// required even if it has `omitempty`.
+//
+// Named struct types other than the root type are added to `$defs`
+// and referenced with `$ref`, which allows recursive types.
+func Generate(t reflect.Type, opts ...Options) (*Schema, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can only generate schemas for structs, but got: %v", t)
+	}
+
+	g := NewGenerator(opts...)
+	g.root = t
+	s, err := g.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Schema = Draft
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+
+	return s, nil
+}
+
+// Generator builds schemas for several types sharing
+// the same set of definitions between them.
+type Generator struct {
+	opts  Options
+	root  reflect.Type
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+// NewGenerator instantiates a new Generator
+func NewGenerator(opts ...Options) *Generator {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Tag == "" {
+		o.Tag = "json"
+	}
+	if o.ValidationTag == "" {
+		o.ValidationTag = "validate"
+	}
+	if o.RefPrefix == "" {
+		o.RefPrefix = "#/$defs/"
+	}
+
+	return &Generator{
+		opts:  o,
+		defs:  map[string]*Schema{},
+		names: map[reflect.Type]string{},
+	}
+}
+
+// Schema returns the schema for the input type, if it is a named
+// struct type it is added to the definitions and the returned
+// schema will contain only a `$ref` to it.
+func (g *Generator) Schema(t reflect.Type) (*Schema, error) {
+	return g.schemaFor(t)
+}
+
+// Defs returns all the definitions collected so far.
+func (g *Generator) Defs() map[string]*Schema {
+	return g.defs
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *Generator) schemaFor(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: TypeList{"string"}, Format: "date-time"}, nil
+	case isUUID(t):
+		return &Schema{Type: TypeList{"string"}, Format: "uuid"}, nil
+	case t == g.root:
+		return &Schema{Ref: "#"}, nil
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return g.refFor(t)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: TypeList{"boolean"}}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TypeList{"integer"}}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeList{"number"}}, nil
+
+	case reflect.String:
+		return &Schema{Type: TypeList{"string"}}, nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return &Schema{Type: TypeList{"string"}, ContentEncoding: "base64"}, nil
+		}
+
+		items, err := g.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		s := &Schema{Type: TypeList{"array"}, Items: items}
+		if t.Kind() == reflect.Array {
+			s.MinItems = intPtr(t.Len())
+			s.MaxItems = intPtr(t.Len())
+		}
+		return s, nil
+
+	case reflect.Map:
+		values, err := g.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: TypeList{"object"}, AdditionalProperties: values}, nil
+
+	case reflect.Struct:
+		return g.structSchema(t)
+
+	case reflect.Interface:
+		return &Schema{}, nil
+	}
+
+	return nil, fmt.Errorf("cannot generate schema for type %v", t)
+}
+
+func (g *Generator) refFor(t reflect.Type) (*Schema, error) {
+	name, found := g.names[t]
+	if !found {
+		name = t.Name()
+		if _, taken := g.defs[name]; taken {
+			name = strings.ReplaceAll(t.PkgPath(), "/", "_") + "." + name
+		}
+		g.names[t] = name
+
+		// Register the name before building the schema
+		// so that recursive types can reference it:
+		g.defs[name] = &Schema{}
+		s, err := g.structSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		*g.defs[name] = *s
+	}
+
+	return &Schema{Ref: g.opts.RefPrefix + name}, nil
+}
+
+func (g *Generator) structSchema(t reflect.Type) (*Schema, error) {
+	info, err := structi.GetStructInfo(t)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schema{
+		Type:       TypeList{"object"},
+		Properties: map[string]*Schema{},
+	}
+	for _, field := range info.Fields {
+		name, options := tags.SplitOptions(field.Tags[g.opts.Tag])
+		if name == "-" && len(options) == 0 {
+			continue
+		}
+
+		// Just like encoding/json does, the fields of
+		// embedded structs are promoted to the parent:
+		if field.IsEmbeded && name == "" && indirect(field.Type).Kind() == reflect.Struct {
+			embedded, err := g.structSchema(indirect(field.Type))
+			if err != nil {
+				return nil, err
+			}
+			for propName, prop := range embedded.Properties {
+				s.Properties[propName] = prop
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := g.schemaFor(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("error generating schema for field %s: %w", field.Name, err)
+		}
+		prop.Description = field.Tags["description"]
+
+		rules := parseRules(field.Tags[g.opts.ValidationTag])
+		err = applyRules(prop, indirect(field.Type), rules)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing validation rules of field %s: %w", field.Name, err)
+		}
+
+		if example, found := field.Tags["example"]; found {
+			prop.Examples = []any{parseExample(indirect(field.Type), example)}
+		}
+
+		if g.opts.NullablePointers && field.Kind == reflect.Ptr {
+			prop = nullable(prop)
+		}
+
+		_, required := rules["required"]
+		if required || !tags.HasOption(field.Tags[g.opts.Tag], "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+
+		s.Properties[name] = prop
+	}
+
+	return s, nil
+}
+
+// isUUID detects the UUID types used by the most common
+// libraries, e.g. `type UUID [16]byte` or `type UUID string`
+func isUUID(t reflect.Type) bool {
+	return t.Name() == "UUID" &&
+		(t.Kind() == reflect.String || t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8)
+}
+
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{
+			Description: s.Description,
+			Examples:    s.Examples,
+			AnyOf: []*Schema{
+				{Ref: s.Ref},
+				{Type: TypeList{"null"}},
+			},
+		}
+	}
+
+	if len(s.Type) > 0 {
+		s.Type = append(s.Type, "null")
+	}
+	if len(s.Enum) > 0 {
+		s.Enum = append(s.Enum, nil)
+	}
+	return s
+}
+
+// parseExample parses the value of the `example` tag as JSON,
+// unless the field is a string, in which case it is used as is.
+func parseExample(t reflect.Type, example string) any {
+	if t.Kind() == reflect.String {
+		return example
+	}
+
+	var v any
+	err := json.Unmarshal([]byte(example), &v)
+	if err != nil {
+		return example
+	}
+	return v
+}
+
+// parseRules parses validation tags on the format used
+// by the go-playground validator, e.g. `required,min=1,max=10`
+func parseRules(tagValue string) map[string]string {
+	rules := map[string]string{}
+	if tagValue == "" {
+		return rules
+	}
+
+	for _, rule := range strings.Split(tagValue, ",") {
+		name, value, _ := strings.Cut(rule, "=")
+		rules[name] = value
+	}
+	return rules
+}
+
+func applyRules(s *Schema, t reflect.Type, rules map[string]string) error {
+	for _, rule := range []struct {
+		names   []string
+		setters map[reflect.Kind]func(v float64)
+	}{
+		{
+			names: []string{"min", "gte"},
+			setters: map[reflect.Kind]func(v float64){
+				reflect.Float64: func(v float64) { s.Minimum = &v },
+				reflect.String:  func(v float64) { s.MinLength = intPtr(int(v)) },
+				reflect.Slice:   func(v float64) { s.MinItems = intPtr(int(v)) },
+			},
+		},
+		{
+			names: []string{"max", "lte"},
+			setters: map[reflect.Kind]func(v float64){
+				reflect.Float64: func(v float64) { s.Maximum = &v },
+				reflect.String:  func(v float64) { s.MaxLength = intPtr(int(v)) },
+				reflect.Slice:   func(v float64) { s.MaxItems = intPtr(int(v)) },
+			},
+		},
+	} {
+		for _, name := range rule.names {
+			value, found := rules[name]
+			if !found {
+				continue
+			}
+
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("invalid value for rule '%s': %w", name, err)
+			}
+
+			if set := rule.setters[kindGroup(t)]; set != nil {
+				set(f)
+			}
+		}
+	}
+
+	if oneof, found := rules["oneof"]; found {
+		for _, option := range strings.Fields(oneof) {
+			v, err := types.StringToType(t, option)
+			if err != nil {
+				return fmt.Errorf("invalid option for rule 'oneof': %w", err)
+			}
+			s.Enum = append(s.Enum, v.Interface())
+		}
+	}
+
+	return nil
+}
+
+// kindGroup groups the kinds that share the same validation
+// keywords, e.g. all numbers use `minimum` and `maximum`
+func kindGroup(t reflect.Type) reflect.Kind {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	case reflect.Array:
+		return reflect.Slice
+	}
+	return t.Kind()
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func intPtr(i int) *int {
+	return &i
+}
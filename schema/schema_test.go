@@ -0,0 +1,172 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	tt "github.com/vingarcia/structi/internal/testtools"
+	"github.com/vingarcia/structi/schema"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("should generate schemas for simple structs", func(t *testing.T) {
+		type Config struct {
+			Name    string            `json:"name" description:"The name of the service"`
+			Port    int               `json:"port,omitempty" validate:"min=1,max=65535"`
+			Level   string            `json:"level,omitempty" validate:"required,oneof=debug info"`
+			Ratio   float64           `json:"ratio,omitempty"`
+			Enabled bool              `json:"enabled"`
+			Hosts   []string          `json:"hosts,omitempty" validate:"min=1"`
+			Labels  map[string]string `json:"labels,omitempty"`
+			Key     []byte            `json:"key,omitempty"`
+			Started time.Time         `json:"started"`
+			Any     any               `json:"any,omitempty"`
+			Ignored string            `json:"-"`
+			NoTag   *string
+		}
+
+		s, err := schema.Generate(reflect.TypeOf(Config{}))
+		tt.AssertNoErr(t, err)
+		assertJSON(t, s, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"name": {"type": "string", "description": "The name of the service"},
+				"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+				"level": {"type": "string", "enum": ["debug", "info"]},
+				"ratio": {"type": "number"},
+				"enabled": {"type": "boolean"},
+				"hosts": {"type": "array", "items": {"type": "string"}, "minItems": 1},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+				"key": {"type": "string", "contentEncoding": "base64"},
+				"started": {"type": "string", "format": "date-time"},
+				"any": {},
+				"NoTag": {"type": "string"}
+			},
+			"required": ["name", "level", "enabled", "started", "NoTag"]
+		}`)
+	})
+
+	t.Run("should use $defs for named nested structs", func(t *testing.T) {
+		type Address struct {
+			City string `json:"city"`
+		}
+		type User struct {
+			Home   Address   `json:"home"`
+			Work   *Address  `json:"work,omitempty"`
+			Others []Address `json:"others,omitempty"`
+			Inline struct {
+				A int `json:"a"`
+			} `json:"inline"`
+		}
+
+		s, err := schema.Generate(reflect.TypeOf(&User{}))
+		tt.AssertNoErr(t, err)
+		assertJSON(t, s, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"home": {"$ref": "#/$defs/Address"},
+				"work": {"$ref": "#/$defs/Address"},
+				"others": {"type": "array", "items": {"$ref": "#/$defs/Address"}},
+				"inline": {
+					"type": "object",
+					"properties": {"a": {"type": "integer"}},
+					"required": ["a"]
+				}
+			},
+			"required": ["home", "inline"],
+			"$defs": {
+				"Address": {
+					"type": "object",
+					"properties": {"city": {"type": "string"}},
+					"required": ["city"]
+				}
+			}
+		}`)
+	})
+
+	t.Run("should support recursive types", func(t *testing.T) {
+		s, err := schema.Generate(reflect.TypeOf(Tree{}))
+		tt.AssertNoErr(t, err)
+		assertJSON(t, s, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"value": {"type": "integer"},
+				"children": {"type": "array", "items": {"$ref": "#"}},
+				"leaf": {"$ref": "#/$defs/Leaf"}
+			},
+			"required": ["value"],
+			"$defs": {
+				"Leaf": {
+					"type": "object",
+					"properties": {
+						"parent": {"$ref": "#/$defs/Leaf"}
+					}
+				}
+			}
+		}`)
+	})
+
+	t.Run("should promote the fields of embedded structs", func(t *testing.T) {
+		type Base struct {
+			ID int `json:"id"`
+		}
+		type User struct {
+			Base
+			Name string `json:"name"`
+		}
+
+		s, err := schema.Generate(reflect.TypeOf(User{}))
+		tt.AssertNoErr(t, err)
+		assertJSON(t, s, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"id": {"type": "integer"},
+				"name": {"type": "string"}
+			},
+			"required": ["id", "name"]
+		}`)
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		_, err := schema.Generate(reflect.TypeOf(42))
+		tt.AssertErrContains(t, err, "can only generate schemas for structs", "int")
+
+		_, err = schema.Generate(reflect.TypeOf(struct {
+			Callback func() `json:"callback"`
+		}{}))
+		tt.AssertErrContains(t, err, "Callback", "func()")
+
+		_, err = schema.Generate(reflect.TypeOf(struct {
+			Port int `json:"port" validate:"min=notANumber"`
+		}{}))
+		tt.AssertErrContains(t, err, "Port", "min", "notANumber")
+	})
+}
+
+type Tree struct {
+	Value    int     `json:"value"`
+	Children []*Tree `json:"children,omitempty"`
+	Leaf     *Leaf   `json:"leaf,omitempty"`
+}
+
+type Leaf struct {
+	Parent *Leaf `json:"parent,omitempty"`
+}
+
+func assertJSON(t *testing.T, s *schema.Schema, expected string) {
+	t.Helper()
+
+	b, err := json.Marshal(s)
+	tt.AssertNoErr(t, err)
+
+	var got, want any
+	tt.AssertNoErr(t, json.Unmarshal(b, &got))
+	tt.AssertNoErr(t, json.Unmarshal([]byte(expected), &want))
+	tt.AssertEqual(t, got, want)
+}
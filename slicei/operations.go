@@ -0,0 +1,386 @@
+package slicei
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vingarcia/structi/internal/types"
+)
+
+// SubSlice replaces the slice with the subslice `slice[start:end]`
+//
+// The items outside of this range are kept on the underlying array,
+// so use Resize() or Filter() if you need them to be garbage collected.
+func SubSlice(targetSlice any, start int, end int) error {
+	_, v, err := getResizableSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	sliceValue := v.Elem()
+	err = checkRange(sliceValue, start, end)
+	if err != nil {
+		return err
+	}
+
+	sliceValue.Set(sliceValue.Slice(start, end))
+	return nil
+}
+
+// Insert converts the items to the type of the slice items and then
+// inserts them at the position `index` moving the subsequent items forward.
+//
+// Inserting at the position `len(slice)` is equivalent to calling Append().
+func Insert(targetSlice any, index int, items ...any) error {
+	t, v, err := getResizableSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	sliceValue := v.Elem()
+	if index < 0 || index > sliceValue.Len() {
+		return fmt.Errorf("index %d out of range for insertion on %v of length %d", index, t, sliceValue.Len())
+	}
+
+	newItems := reflect.MakeSlice(t, len(items), len(items))
+	for i, item := range items {
+		convertedValue, err := types.NewConverter(item).Convert(t.Elem())
+		if err != nil {
+			return fmt.Errorf("error converting %+v to %v: %w", item, t.Elem(), err)
+		}
+		newItems.Index(i).Set(convertedValue)
+	}
+
+	result := reflect.MakeSlice(t, 0, sliceValue.Len()+len(items))
+	result = reflect.AppendSlice(result, sliceValue.Slice(0, index))
+	result = reflect.AppendSlice(result, newItems)
+	result = reflect.AppendSlice(result, sliceValue.Slice(index, sliceValue.Len()))
+
+	sliceValue.Set(result)
+	return nil
+}
+
+// Delete removes the items `slice[start:end]` from the slice
+// moving the subsequent items backwards.
+func Delete(targetSlice any, start int, end int) error {
+	t, v, err := getResizableSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	sliceValue := v.Elem()
+	err = checkRange(sliceValue, start, end)
+	if err != nil {
+		return err
+	}
+
+	length := sliceValue.Len()
+	reflect.Copy(sliceValue.Slice(start, length), sliceValue.Slice(end, length))
+
+	newLength := length - (end - start)
+	clearItems(sliceValue.Slice(newLength, length), t.Elem())
+	sliceValue.Set(sliceValue.Slice(0, newLength))
+	return nil
+}
+
+// Truncate discards all the items after the first `length` items
+// of the slice, it returns an error if the slice is shorter than that.
+func Truncate(targetSlice any, length int) error {
+	t, v, err := getResizableSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	sliceValue := v.Elem()
+	if length < 0 || length > sliceValue.Len() {
+		return fmt.Errorf("cannot truncate %v of length %d to length %d", t, sliceValue.Len(), length)
+	}
+
+	return Delete(targetSlice, length, sliceValue.Len())
+}
+
+// Resize changes the length of the slice, adding zero
+// valued items at the end of it or removing items as needed.
+func Resize(targetSlice any, length int) error {
+	t, v, err := getResizableSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	if length < 0 {
+		return fmt.Errorf("cannot resize %v to negative length %d", t, length)
+	}
+
+	sliceValue := v.Elem()
+	if length <= sliceValue.Len() {
+		return Truncate(targetSlice, length)
+	}
+
+	zeroItems := reflect.MakeSlice(t, length-sliceValue.Len(), length-sliceValue.Len())
+	sliceValue.Set(reflect.AppendSlice(sliceValue, zeroItems))
+	return nil
+}
+
+// Reverse reverses the order of the items of the slice (or array) in place
+func Reverse(targetSlice any) error {
+	_, v, err := getSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	sliceValue := v.Elem()
+	swap := reflect.Swapper(sliceValue.Slice(0, sliceValue.Len()).Interface())
+	for i, j := 0, sliceValue.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+
+	return nil
+}
+
+// Filter removes from the slice all the items for which
+// the `keep` function returns false, preserving the order
+// of the remaining items.
+//
+// The Field received by the `keep` function works the same as
+// the one received by ForEach(), so it can also be used to update
+// the items that are kept.
+func Filter(targetSlice any, keep func(field Field) (bool, error)) error {
+	t, v, err := getResizableSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	sliceValue := v.Elem()
+	itemType := t.Elem()
+	length := sliceValue.Len()
+
+	kept := 0
+	for i := 0; i < length; i++ {
+		ok, err := keep(Field{
+			Index: i,
+			Kind:  itemType.Kind(),
+			Type:  itemType,
+			Value: sliceValue.Index(i).Addr().Interface(),
+
+			Set: setItemValue(sliceValue, itemType, i),
+		})
+		if err != nil {
+			return fmt.Errorf("filter error on item '%d' of type '%v': %w", i, itemType, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		if kept != i {
+			sliceValue.Index(kept).Set(sliceValue.Index(i))
+		}
+		kept++
+	}
+
+	clearItems(sliceValue.Slice(kept, length), itemType)
+	sliceValue.Set(sliceValue.Slice(0, kept))
+	return nil
+}
+
+// Map calls the `mapper` function for each item of the source
+// slice (or array) and then stores the returned values on the
+// target slice converting them to the target item type.
+//
+// The target slice is replaced by a new slice with
+// the same length of the source slice.
+func Map(sourceSlice any, targetSlice any, mapper func(field Field) (any, error)) error {
+	if sourceSlice == nil {
+		return fmt.Errorf("unexpected nil source")
+	}
+
+	src := reflect.ValueOf(sourceSlice)
+	if src.Kind() == reflect.Ptr {
+		src = src.Elem()
+	}
+	if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+		return fmt.Errorf("expected source to be a slice or array but got: %v", src.Type())
+	}
+
+	t, v, err := getResizableSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	// Arrays received by value are not addressable,
+	// so we copy them to be able to build the Field.Value:
+	if !src.CanAddr() && src.Kind() == reflect.Array {
+		tmp := reflect.New(src.Type()).Elem()
+		tmp.Set(src)
+		src = tmp
+	}
+
+	length := src.Len()
+	itemType := src.Type().Elem()
+	result := reflect.MakeSlice(t, length, length)
+	for i := 0; i < length; i++ {
+		value, err := mapper(Field{
+			Index: i,
+			Kind:  itemType.Kind(),
+			Type:  itemType,
+			Value: src.Index(i).Addr().Interface(),
+
+			Set: setItemValue(src, itemType, i),
+		})
+		if err != nil {
+			return fmt.Errorf("map error on item '%d' of type '%v': %w", i, itemType, err)
+		}
+
+		convertedValue, err := types.NewConverter(value).Convert(t.Elem())
+		if err != nil {
+			return fmt.Errorf("error converting result of item '%d' to %v: %w", i, t.Elem(), err)
+		}
+		result.Index(i).Set(convertedValue)
+	}
+
+	v.Elem().Set(result)
+	return nil
+}
+
+// SortBy sorts a slice (or array) of structs by the value of one of
+// their fields, the fieldPath can point to nested structs, e.g. "User.Name".
+//
+// The sorting is stable and nil pointers along the path are
+// sorted before any non-nil values.
+func SortBy(targetSlice any, fieldPath string) error {
+	t, v, err := getSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	fieldIndexes, err := resolveFieldPath(t.Elem(), fieldPath)
+	if err != nil {
+		return err
+	}
+
+	sliceValue := v.Elem()
+	sliceValue = sliceValue.Slice(0, sliceValue.Len())
+	sort.SliceStable(sliceValue.Interface(), func(i, j int) bool {
+		a, aOk := fieldByIndexes(sliceValue.Index(i), fieldIndexes)
+		b, bOk := fieldByIndexes(sliceValue.Index(j), fieldIndexes)
+		if !aOk || !bOk {
+			return !aOk && bOk
+		}
+		return less(a, b)
+	})
+
+	return nil
+}
+
+// resolveFieldPath validates the fieldPath and returns the
+// indexes of the fields that lead to it on each nested struct.
+func resolveFieldPath(t reflect.Type, fieldPath string) ([][]int, error) {
+	fieldIndexes := [][]int{}
+	for _, name := range strings.Split(fieldPath, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("cannot sort by '%s': expected struct but got: %v", fieldPath, t)
+		}
+
+		field, found := t.FieldByName(name)
+		if !found || !field.IsExported() {
+			return nil, fmt.Errorf("cannot sort by '%s': no exported field '%s' on type %v", fieldPath, name, t)
+		}
+
+		fieldIndexes = append(fieldIndexes, field.Index)
+		t = field.Type
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
+		return fieldIndexes, nil
+	}
+
+	return nil, fmt.Errorf("cannot sort by '%s': values of type %v are not ordered", fieldPath, t)
+}
+
+// fieldByIndexes returns false if it finds a nil pointer along the path
+func fieldByIndexes(v reflect.Value, fieldIndexes [][]int) (reflect.Value, bool) {
+	for _, index := range fieldIndexes {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+
+		field, err := v.FieldByIndexErr(index)
+		if err != nil {
+			// This happens on nil embedded struct pointers
+			return reflect.Value{}, false
+		}
+		v = field
+	}
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+
+	return v, true
+}
+
+func less(a reflect.Value, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	}
+	return false
+}
+
+func checkRange(sliceValue reflect.Value, start int, end int) error {
+	if start < 0 || end < start || end > sliceValue.Len() {
+		return fmt.Errorf("invalid range [%d:%d] for %v of length %d", start, end, sliceValue.Type(), sliceValue.Len())
+	}
+	return nil
+}
+
+// clearItems sets the items to zero so that the underlying array
+// doesn't keep references to values that were removed from the slice.
+func clearItems(items reflect.Value, itemType reflect.Type) {
+	zero := reflect.Zero(itemType)
+	for i := 0; i < items.Len(); i++ {
+		items.Index(i).Set(zero)
+	}
+}
+
+// getResizableSliceInfo works like getSliceInfo
+// but returns an error for arrays.
+func getResizableSliceInfo(targetSlice any) (reflect.Type, reflect.Value, error) {
+	t, v, err := getSliceInfo(targetSlice)
+	if err != nil {
+		return nil, reflect.Value{}, err
+	}
+
+	if t.Kind() == reflect.Array {
+		return nil, reflect.Value{}, fmt.Errorf("cannot change the length of arrays, but got: %v", v.Type())
+	}
+
+	return t, v, nil
+}
@@ -0,0 +1,253 @@
+package slicei_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	tt "github.com/vingarcia/structi/internal/testtools"
+	"github.com/vingarcia/structi/slicei"
+)
+
+func TestSubSlice(t *testing.T) {
+	t.Run("should cut the slice in place", func(t *testing.T) {
+		input := []int{1, 2, 3, 4}
+		err := slicei.SubSlice(&input, 1, 3)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []int{2, 3})
+	})
+
+	t.Run("should report error for invalid ranges", func(t *testing.T) {
+		input := []int{1, 2, 3}
+		err := slicei.SubSlice(&input, 2, 1)
+		tt.AssertErrContains(t, err, "invalid range", "[2:1]", "[]int")
+
+		err = slicei.SubSlice(&input, 0, 4)
+		tt.AssertErrContains(t, err, "invalid range", "[0:4]", "length 3")
+	})
+
+	t.Run("should report error for arrays", func(t *testing.T) {
+		input := [3]int{1, 2, 3}
+		err := slicei.SubSlice(&input, 0, 1)
+		tt.AssertErrContains(t, err, "arrays", "[3]int")
+	})
+}
+
+func TestInsert(t *testing.T) {
+	t.Run("should insert and convert items", func(t *testing.T) {
+		input := []int{1, 4}
+		err := slicei.Insert(&input, 1, 2.0, int8(3))
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []int{1, 2, 3, 4})
+
+		err = slicei.Insert(&input, 4, 5)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []int{1, 2, 3, 4, 5})
+	})
+
+	t.Run("should work with nil slices", func(t *testing.T) {
+		var input []string
+		err := slicei.Insert(&input, 0, "foo")
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []string{"foo"})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		input := []int{1, 2}
+		err := slicei.Insert(&input, 3, 42)
+		tt.AssertErrContains(t, err, "index 3", "[]int", "length 2")
+
+		err = slicei.Insert(&input, 0, struct{}{})
+		tt.AssertErrContains(t, err, "converting", "struct", "int")
+		tt.AssertEqual(t, input, []int{1, 2})
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("should remove the items in the range", func(t *testing.T) {
+		input := []string{"a", "b", "c", "d"}
+		backingArray := input
+		err := slicei.Delete(&input, 1, 3)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []string{"a", "d"})
+
+		// The removed positions should be cleared:
+		tt.AssertEqual(t, backingArray, []string{"a", "d", "", ""})
+	})
+
+	t.Run("should report error for invalid ranges", func(t *testing.T) {
+		input := []string{"a"}
+		err := slicei.Delete(&input, 0, 2)
+		tt.AssertErrContains(t, err, "invalid range", "[0:2]")
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	t.Run("should discard the items after the given length", func(t *testing.T) {
+		input := []int{1, 2, 3}
+		err := slicei.Truncate(&input, 1)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []int{1})
+	})
+
+	t.Run("should report error if the slice is shorter than the length", func(t *testing.T) {
+		input := []int{1, 2, 3}
+		err := slicei.Truncate(&input, 4)
+		tt.AssertErrContains(t, err, "cannot truncate", "[]int", "length 3", "length 4")
+	})
+}
+
+func TestResize(t *testing.T) {
+	t.Run("should grow the slice with zero values", func(t *testing.T) {
+		input := []*int{ptr(1)}
+		err := slicei.Resize(&input, 3)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []*int{ptr(1), nil, nil})
+	})
+
+	t.Run("should shrink the slice", func(t *testing.T) {
+		input := []int{1, 2, 3}
+		err := slicei.Resize(&input, 2)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []int{1, 2})
+	})
+
+	t.Run("should report error for negative lengths", func(t *testing.T) {
+		input := []int{1}
+		err := slicei.Resize(&input, -1)
+		tt.AssertErrContains(t, err, "negative length", "-1")
+	})
+}
+
+func TestReverse(t *testing.T) {
+	t.Run("should reverse slices and arrays", func(t *testing.T) {
+		slice := []int{1, 2, 3, 4}
+		err := slicei.Reverse(&slice)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, slice, []int{4, 3, 2, 1})
+
+		array := [3]string{"a", "b", "c"}
+		err = slicei.Reverse(&array)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, array, [3]string{"c", "b", "a"})
+	})
+}
+
+func TestFilter(t *testing.T) {
+	t.Run("should keep only the selected items", func(t *testing.T) {
+		input := []int{1, 2, 3, 4, 5}
+		err := slicei.Filter(&input, func(f slicei.Field) (bool, error) {
+			return *f.Value.(*int)%2 == 1, nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []int{1, 3, 5})
+	})
+
+	t.Run("should allow updating the items that are kept", func(t *testing.T) {
+		input := []string{"a", "b", "c"}
+		err := slicei.Filter(&input, func(f slicei.Field) (bool, error) {
+			return f.Index != 1, f.Set(strconv.Itoa(f.Index))
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []string{"0", "2"})
+	})
+
+	t.Run("should report errors from the filter function", func(t *testing.T) {
+		input := []int{1, 2}
+		err := slicei.Filter(&input, func(f slicei.Field) (bool, error) {
+			return false, fmt.Errorf("fakeErrMsg")
+		})
+		tt.AssertErrContains(t, err, "filter error", "item '0'", "int", "fakeErrMsg")
+	})
+}
+
+func TestMap(t *testing.T) {
+	t.Run("should map items into another slice type", func(t *testing.T) {
+		input := []int{1, 2, 3}
+
+		var output []string
+		err := slicei.Map(input, &output, func(f slicei.Field) (any, error) {
+			return fmt.Sprint(*f.Value.(*int) * 10), nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, []string{"10", "20", "30"})
+	})
+
+	t.Run("should convert the returned values to the target item type", func(t *testing.T) {
+		var output []float64
+		err := slicei.Map([2]int{1, 2}, &output, func(f slicei.Field) (any, error) {
+			return f.Value, nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output, []float64{1, 2})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		var output []int
+		err := slicei.Map([]int{1}, &output, func(f slicei.Field) (any, error) {
+			return nil, fmt.Errorf("fakeErrMsg")
+		})
+		tt.AssertErrContains(t, err, "map error", "item '0'", "fakeErrMsg")
+
+		err = slicei.Map([]int{1}, &output, func(f slicei.Field) (any, error) {
+			return struct{}{}, nil
+		})
+		tt.AssertErrContains(t, err, "converting", "item '0'", "struct", "int")
+
+		err = slicei.Map(42, &output, func(f slicei.Field) (any, error) {
+			return nil, nil
+		})
+		tt.AssertErrContains(t, err, "expected source", "int")
+	})
+}
+
+func TestSortBy(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type User struct {
+		Name    string
+		Age     int
+		Address *Address
+	}
+
+	t.Run("should sort by a field in a stable way", func(t *testing.T) {
+		input := []User{
+			{Name: "c", Age: 30},
+			{Name: "a", Age: 20},
+			{Name: "b", Age: 30},
+			{Name: "d", Age: 10},
+		}
+		err := slicei.SortBy(&input, "Age")
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, input, []User{
+			{Name: "d", Age: 10},
+			{Name: "a", Age: 20},
+			{Name: "c", Age: 30},
+			{Name: "b", Age: 30},
+		})
+	})
+
+	t.Run("should sort by nested fields with nil pointers first", func(t *testing.T) {
+		input := []*User{
+			{Name: "a", Address: &Address{City: "Rio"}},
+			{Name: "b"},
+			{Name: "c", Address: &Address{City: "Paris"}},
+		}
+		err := slicei.SortBy(&input, "Address.City")
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, []string{input[0].Name, input[1].Name, input[2].Name}, []string{"b", "c", "a"})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		input := []User{}
+		err := slicei.SortBy(&input, "Missing")
+		tt.AssertErrContains(t, err, "cannot sort", "Missing", "User")
+
+		err = slicei.SortBy(&input, "Address")
+		tt.AssertErrContains(t, err, "cannot sort", "Address", "not ordered")
+
+		err = slicei.SortBy(&input, "Name.Foo")
+		tt.AssertErrContains(t, err, "cannot sort", "Name.Foo", "expected struct", "string")
+	})
+}
@@ -23,12 +23,20 @@ type Field struct {
 	Set func(value any) error
 }
 
+// Append converts each of the items to the type of the slice items
+// and then appends them to the slice.
+//
+// Arrays are not supported since their length is fixed.
 func Append(targetSlice any, items ...any) error {
 	t, v, err := getSliceInfo(targetSlice)
 	if err != nil {
 		return err
 	}
 
+	if t.Kind() == reflect.Array {
+		return fmt.Errorf("cannot append to arrays, but got: %v", v.Type())
+	}
+
 	elemType := t.Elem()
 	sliceValue := v.Elem()
 	for _, item := range items {
@@ -45,8 +53,8 @@ func Append(targetSlice any, items ...any) error {
 	return nil
 }
 
-// ForEach iterates over the slice calling the iterate function
-// for each item
+// ForEach iterates over the slice (or array) calling
+// the iterate function for each item
 func ForEach(targetSlice interface{}, iterate IteratorFunc) error {
 	_, v, err := getSliceInfo(targetSlice)
 	if err != nil {
@@ -72,6 +80,77 @@ func ForEach(targetSlice interface{}, iterate IteratorFunc) error {
 	return nil
 }
 
+// Set converts the value to the type of the slice (or array)
+// items and then stores it on the position `index`.
+func Set(targetSlice any, index int, value any) error {
+	_, v, err := getSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	sliceValue := v.Elem()
+	if index < 0 || index >= sliceValue.Len() {
+		return fmt.Errorf("index %d out of range for %v of length %d", index, sliceValue.Type(), sliceValue.Len())
+	}
+
+	return setItemValue(sliceValue, sliceValue.Type().Elem(), index)(value)
+}
+
+// CopyOptions allows the user to customize the behavior of Copy()
+type CopyOptions struct {
+	// Truncate makes Copy() discard the items that don't fit on the
+	// target array instead of returning an error.
+	Truncate bool
+}
+
+// Copy converts the items of the source slice (or array) to
+// the item type of the target slice (or array) and stores them on it.
+//
+// If the target is a slice its length will match the source,
+// if it is an array any remaining positions are set to zero
+// and an error is returned if the source has more items than
+// the array can hold, unless the Truncate option is set.
+func Copy(targetSlice any, sourceSlice any, opts ...CopyOptions) error {
+	var o CopyOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	t, v, err := getSliceInfo(targetSlice)
+	if err != nil {
+		return err
+	}
+
+	if sourceSlice == nil {
+		return fmt.Errorf("unexpected nil source")
+	}
+
+	src := reflect.ValueOf(sourceSlice)
+	if src.Kind() == reflect.Ptr {
+		src = src.Elem()
+	}
+	if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+		return fmt.Errorf("expected source to be a slice or array but got: %v", src.Type())
+	}
+
+	if o.Truncate && t.Kind() == reflect.Array && src.Len() > t.Len() {
+		if src.Kind() == reflect.Array && !src.CanAddr() {
+			tmp := reflect.New(src.Type()).Elem()
+			tmp.Set(src)
+			src = tmp
+		}
+		src = src.Slice(0, t.Len())
+	}
+
+	convertedValue, err := types.NewConverter(src.Interface()).Convert(t)
+	if err != nil {
+		return fmt.Errorf("error copying %v into %v: %w", src.Type(), t, err)
+	}
+
+	v.Elem().Set(convertedValue)
+	return nil
+}
+
 func setItemValue(sliceValue reflect.Value, itemType reflect.Type, index int) func(value any) error {
 	return func(value any) error {
 		convertedValue, err := types.NewConverter(value).Convert(itemType)
@@ -100,8 +179,12 @@ func getSliceInfo(targetSlice interface{}) (reflect.Type, reflect.Value, error)
 	}
 
 	t := ptrType.Elem()
-	if t.Kind() != reflect.Slice {
-		return nil, reflect.Value{}, fmt.Errorf("can only get slice info from slices, but got: %s", ptrType)
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, reflect.Value{}, fmt.Errorf("can only get slice info from slices or arrays, but got: %s", ptrType)
+	}
+
+	if v.IsNil() {
+		return nil, reflect.Value{}, fmt.Errorf("unexpected nil pointer to %v", t)
 	}
 
 	return t, v, nil
@@ -61,6 +61,12 @@ func TestSliceAppend(t *testing.T) {
 		err := slicei.Append(nil, 42)
 		tt.AssertErrContains(t, err, "unexpected nil input")
 	})
+
+	t.Run("should report error if input is an array", func(t *testing.T) {
+		input := [2]int{1, 2}
+		err := slicei.Append(&input, 3)
+		tt.AssertErrContains(t, err, "cannot append", "[2]int")
+	})
 }
 
 func TestSliceForEach(t *testing.T) {
@@ -126,6 +132,17 @@ func TestSliceForEach(t *testing.T) {
 		tt.AssertEqual(t, i, 0)
 	})
 
+	t.Run("should iterate over arrays", func(t *testing.T) {
+		input := [3]int{1, 2, 3}
+
+		err := slicei.ForEach(&input, func(f slicei.Field) error {
+			return f.Set(*f.Value.(*int) * 10)
+		})
+		tt.AssertNoErr(t, err)
+
+		tt.AssertEqual(t, input, [3]int{10, 20, 30})
+	})
+
 	t.Run("validation errors", func(t *testing.T) {
 		tests := []struct {
 			desc               string
@@ -172,6 +189,74 @@ func TestSliceForEach(t *testing.T) {
 	})
 }
 
+func TestSliceSet(t *testing.T) {
+	t.Run("should set and convert values on slices and arrays", func(t *testing.T) {
+		slice := []int{1, 2, 3}
+		err := slicei.Set(&slice, 1, 4.0)
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, slice, []int{1, 4, 3})
+
+		array := [2]string{"a", "b"}
+		err = slicei.Set(&array, 0, "c")
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, array, [2]string{"c", "b"})
+	})
+
+	t.Run("should report error if the index is out of range", func(t *testing.T) {
+		slice := []int{1, 2, 3}
+		err := slicei.Set(&slice, 3, 4)
+		tt.AssertErrContains(t, err, "index 3", "[]int", "length 3")
+
+		err = slicei.Set(&slice, -1, 4)
+		tt.AssertErrContains(t, err, "index -1")
+	})
+
+	t.Run("should report error if conversion is not possible", func(t *testing.T) {
+		slice := []int{1, 2, 3}
+		err := slicei.Set(&slice, 0, struct{}{})
+		tt.AssertErrContains(t, err, "[]int[0]", "cannot convert", "struct")
+	})
+}
+
+func TestSliceCopy(t *testing.T) {
+	t.Run("should copy slices into arrays", func(t *testing.T) {
+		var array [3]float64
+		err := slicei.Copy(&array, []int{1, 2})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, array, [3]float64{1, 2, 0})
+	})
+
+	t.Run("should copy arrays into slices", func(t *testing.T) {
+		var slice []string
+		err := slicei.Copy(&slice, &[2]string{"a", "b"})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, slice, []string{"a", "b"})
+	})
+
+	t.Run("should report error if the source doesn't fit on the array", func(t *testing.T) {
+		var array [2]int
+		err := slicei.Copy(&array, []int{1, 2, 3})
+		tt.AssertErrContains(t, err, "3 items", "[2]int")
+	})
+
+	t.Run("should truncate the source if requested", func(t *testing.T) {
+		var array [2]int
+		err := slicei.Copy(&array, []int{1, 2, 3}, slicei.CopyOptions{Truncate: true})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, array, [2]int{1, 2})
+
+		err = slicei.Copy(&array, [3]int{4, 5, 6}, slicei.CopyOptions{Truncate: true})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, array, [2]int{4, 5})
+	})
+
+	t.Run("should report error if the source is not a slice", func(t *testing.T) {
+		var slice []int
+		err := slicei.Copy(&slice, 42)
+		tt.AssertErrContains(t, err, "expected source", "slice", "int")
+	})
+}
+
 func ptr[T any](t T) *T {
 	return &t
 }
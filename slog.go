@@ -0,0 +1,140 @@
+package structi
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+// LogValue converts the input struct (or pointer to struct) into a
+// slog group so that it can be used for structured logging, e.g.:
+//
+//	slog.Info("request received", "request", structi.LogValue(req))
+//
+// The keys are read from the `log` tag, falling back to the field name,
+// fields tagged with `log:"-"` are omitted and nested structs are
+// rendered as nested groups. Secret fields are masked the same way
+// as on Redact(), and the RedactOptions can be used for choosing
+// the mask, the secret names pattern or a different tag.
+//
+// If the struct can't be converted the returned value is a string
+// describing the error, since log calls have no way of reporting it.
+func LogValue(targetStruct any, opts ...RedactOptions) slog.Value {
+	var o RedactOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if o.Tag == "" {
+		o.Tag = "log"
+	}
+
+	r := newRedactor([]RedactOptions{o})
+
+	v := reflect.ValueOf(targetStruct)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		r.visiting[visitKey{ptr: v.Pointer(), t: v.Type()}] = true
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return slog.StringValue(fmt.Sprintf("!REDACT_ERROR(can only log structs, but got: %T)", targetStruct))
+	}
+
+	attrs, err := r.logAttrs(v)
+	if err != nil {
+		return slog.StringValue(fmt.Sprintf("!REDACT_ERROR(%s)", err))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+func (r redactor) logAttrs(v reflect.Value) ([]slog.Attr, error) {
+	_, fields, err := getStructInfoForType(reflect.PointerTo(v.Type()))
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []slog.Attr{}
+	for _, field := range fields {
+		fieldValue := v.Field(field.idx)
+
+		key, tagName := fieldKey(field, r.opts.Tag)
+		if tagName == "-" {
+			continue
+		}
+
+		if r.isSecret(field) {
+			attrs = append(attrs, slog.String(key, r.opts.Mask))
+			continue
+		}
+
+		if elem, ok := promotedStruct(field, tagName, fieldValue); ok {
+			embeddedAttrs, err := r.logAttrs(elem)
+			if err != nil {
+				return nil, err
+			}
+			attrs = append(attrs, embeddedAttrs...)
+			continue
+		}
+
+		value, err := r.logValue(fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("error redacting field '%s': %w", field.Name, err)
+		}
+		attrs = append(attrs, slog.Attr{Key: key, Value: value})
+	}
+
+	return attrs, nil
+}
+
+func (r redactor) logValue(v reflect.Value) (slog.Value, error) {
+	// Types with their own LogValue method know better how to log themselves:
+	if v.Kind() != reflect.Interface && v.CanInterface() {
+		if valuer, ok := v.Interface().(slog.LogValuer); ok {
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				return slog.AnyValue(nil), nil
+			}
+			return slog.AnyValue(valuer), nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return slog.AnyValue(nil), nil
+		}
+
+		key := visitKey{ptr: v.Pointer(), t: v.Type()}
+		if r.visiting[key] {
+			return slog.StringValue(fmt.Sprintf("<cycle %v>", v.Type())), nil
+		}
+		r.visiting[key] = true
+		defer delete(r.visiting, key)
+
+		return r.logValue(v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return slog.AnyValue(nil), nil
+		}
+		return r.logValue(v.Elem())
+
+	case reflect.Struct:
+		if isOpaqueStruct(v.Type()) {
+			return slog.AnyValue(v.Interface()), nil
+		}
+
+		attrs, err := r.logAttrs(v)
+		if err != nil {
+			return slog.Value{}, err
+		}
+		return slog.GroupValue(attrs...), nil
+	}
+
+	value, err := r.redactValue(v)
+	if err != nil {
+		return slog.Value{}, err
+	}
+	return slog.AnyValue(value), nil
+}
@@ -0,0 +1,118 @@
+package structi_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+type logUserID int
+
+func (id logUserID) LogValue() slog.Value {
+	return slog.StringValue("user-" + strconv.Itoa(int(id)))
+}
+
+func TestLogValue(t *testing.T) {
+	type Pool struct {
+		MaxConns int `log:"max_conns"`
+	}
+	type DB struct {
+		Host     string `log:"host"`
+		Password string `log:"password"`
+		Pool     *Pool  `log:"pool"`
+	}
+
+	t.Run("should render structs as groups", func(t *testing.T) {
+		input := struct {
+			Name      string `log:"name"`
+			Token     string `log:"token"`
+			DB        DB     `log:"db"`
+			Internal  string `log:"-"`
+			UserID    logUserID
+			Tags      []string
+			CreatedAt time.Time
+		}{
+			Name:      "app",
+			Token:     "fakeToken",
+			DB:        DB{Host: "localhost", Password: "fakePass", Pool: &Pool{MaxConns: 10}},
+			Internal:  "foo",
+			UserID:    42,
+			Tags:      []string{"a", "b"},
+			CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		}
+
+		value := structi.LogValue(&input)
+		tt.AssertEqual(t, value.Kind(), slog.KindGroup)
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		logger.Info("starting", "config", value)
+		tt.AssertEqual(t, buf.String(), "msg=starting"+
+			" config.name=app"+
+			" config.token=[REDACTED]"+
+			" config.db.host=localhost"+
+			" config.db.password=[REDACTED]"+
+			" config.db.pool.max_conns=10"+
+			" config.UserID=user-42"+
+			" config.Tags=\"[a b]\""+
+			" config.CreatedAt=2024-01-02T00:00:00.000Z\n",
+		)
+	})
+
+	t.Run("should promote embedded fields and stop on cycles", func(t *testing.T) {
+		type Base struct {
+			ID int `log:"id"`
+		}
+		type Node struct {
+			Base
+			Parent *Node `log:"parent"`
+		}
+		root := &Node{Base: Base{ID: 1}}
+		root.Parent = root
+
+		attrs := structi.LogValue(root).Group()
+		tt.AssertEqual(t, len(attrs), 2)
+		tt.AssertEqual(t, attrs[0].String(), "id=1")
+		tt.AssertEqual(t, attrs[1].String(), "parent=<cycle *structi_test.Node>")
+	})
+
+	t.Run("should allow customizing the options", func(t *testing.T) {
+		input := struct {
+			Name string `json:"name" log:"ignored"`
+			Pin  int    `json:"pin" secret:"true"`
+		}{
+			Name: "foo",
+			Pin:  1234,
+		}
+
+		attrs := structi.LogValue(input, structi.RedactOptions{
+			Tag:  "json",
+			Mask: "***",
+		}).Group()
+		tt.AssertEqual(t, len(attrs), 2)
+		tt.AssertEqual(t, attrs[0].String(), "name=foo")
+		tt.AssertEqual(t, attrs[1].String(), "pin=***")
+	})
+
+	t.Run("should describe errors on the returned value", func(t *testing.T) {
+		value := structi.LogValue(42)
+		tt.AssertEqual(t, value.String(), "!REDACT_ERROR(can only log structs, but got: int)")
+
+		value = structi.LogValue(struct {
+			Callback func()
+		}{})
+		tt.AssertEqual(t, value.String(), "!REDACT_ERROR(error redacting field 'Callback': cannot redact values of type func())")
+	})
+}
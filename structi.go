@@ -59,12 +59,30 @@ func GetStructInfo(targetStruct interface{}) (si StructInfo, err error) {
 
 // ForEach iterates over the attributes of the input struct calling
 // the `iterate` function for each attribute
+//
+// The input can also be a pointer to an interface holding a struct,
+// e.g. the Field.Value of an interface field, in which case ForEach
+// iterates over the attributes of the struct stored in the interface.
 func ForEach(targetStruct interface{}, iterate IteratorFunc) error {
+	ptr, ok := targetStruct.(reflect.Value)
+	if !ok {
+		ptr = reflect.ValueOf(targetStruct)
+	}
+	if ptr.Kind() == reflect.Ptr && !ptr.IsNil() && ptr.Elem().Kind() == reflect.Interface {
+		return forEachInterface(ptr, func(structPtr reflect.Value) error {
+			return ForEach(structPtr, iterate)
+		})
+	}
+
 	_, v, fields, err := getStructInfo(targetStruct)
 	if err != nil {
 		return err
 	}
 
+	if gen, found := generatedIterators.Load(v.Type()); found {
+		return gen.(generatedIterator).forEach(v, iterate)
+	}
+
 	for _, field := range fields {
 		err := iterate(Field{
 			fieldInfo: &field,
@@ -79,48 +97,43 @@ func ForEach(targetStruct interface{}, iterate IteratorFunc) error {
 	return nil
 }
 
+// SetLenientNumericConversion allows Field.Set() and the other functions
+// that convert values to silently overflow or truncate numbers, e.g.
+// setting 300 on an uint8 field results in 44 and 3.9 on an int field
+// results in 3.
+//
+// By default these conversions return an error instead.
+//
+// Note that this is a process-wide setting that also affects any other
+// package using structi, so it should only be set once when the program
+// starts and not toggled at runtime. Prefer the `convert:"lenient"` tag
+// for allowing these conversions on specific fields.
+func SetLenientNumericConversion(enabled bool) {
+	types.SetLenientNumericConversion(enabled)
+}
+
 func setAttrValue(structPtrValue reflect.Value, field fieldInfo) func(value any) error {
 	return func(value any) error {
-		if field.Kind != reflect.Slice {
-			convertedValue, err := types.NewConverter(value).Convert(field.Type)
-			if err != nil {
-				return err
-			}
-
-			structPtrValue.Elem().Field(field.idx).Set(convertedValue)
-			return nil
+		converter := types.NewConverter(value).
+			WithTimeLayout(field.Tags["layout"]).
+			WithLenientNumbers(field.Tags["convert"] == "lenient")
+
+		var convertedValue reflect.Value
+		var err error
+		if discriminatorKey := field.Tags["union"]; discriminatorKey != "" {
+			convertedValue, err = converter.ConvertUnion(field.Type, discriminatorKey)
+		} else {
+			convertedValue, err = converter.Convert(field.Type)
 		}
-
-		// In case it is a slice
-
-		sliceValue := reflect.ValueOf(value)
-		sliceType := sliceValue.Type()
-		if sliceType.Kind() == reflect.Ptr {
-			sliceType = sliceType.Elem()
-			sliceValue = sliceValue.Elem()
-		}
-
-		if sliceType.Kind() != reflect.Slice {
-			t := structPtrValue.Elem().Type()
-			return fmt.Errorf("expected slice for field %#v but got %v of type %v", t.Field(field.idx), sliceValue, sliceType)
+		if _, ok := err.(*types.PathError); ok {
+			// Include the field name on the path of nested errors, e.g. `Items[3].Price`
+			return types.WithPathPrefix(field.Name, err)
 		}
-
-		elemType := field.Type.Elem()
-
-		sliceLen := sliceValue.Len()
-		targetSlice := reflect.MakeSlice(field.Type, sliceLen, sliceLen)
-		for i := 0; i < sliceLen; i++ {
-			convertedValue, err := types.NewConverter(sliceValue.Index(i).Interface()).Convert(elemType)
-			if err != nil {
-				t := structPtrValue.Elem().Type()
-				return fmt.Errorf("error converting %v[%d]: %w", t.Field(field.idx).Name, i, err)
-			}
-
-			targetSlice.Index(i).Set(convertedValue)
+		if err != nil {
+			return err
 		}
 
-		structPtrValue.Elem().Field(field.idx).Set(targetSlice)
-
+		structPtrValue.Elem().Field(field.idx).Set(convertedValue)
 		return nil
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/vingarcia/structi"
 	tt "github.com/vingarcia/structi/internal/testtools"
@@ -235,7 +236,76 @@ func TestForEach(t *testing.T) {
 			})
 		})
 
+		t.Run("should work with arrays", func(t *testing.T) {
+			t.Run("source slice target array", func(t *testing.T) {
+				var output struct {
+					Array [3]int `map:"array"`
+				}
+				err := structi.ForEach(&output, func(field structi.Field) error {
+					return field.Set([]any{1, 2.0})
+				})
+				tt.AssertNoErr(t, err)
+				tt.AssertEqual(t, output.Array, [3]int{1, 2, 0})
+			})
+
+			t.Run("source array target slice", func(t *testing.T) {
+				var output struct {
+					Slice []float64 `map:"slice"`
+				}
+				err := structi.ForEach(&output, func(field structi.Field) error {
+					return field.Set([2]int{1, 2})
+				})
+				tt.AssertNoErr(t, err)
+				tt.AssertEqual(t, output.Slice, []float64{1, 2})
+			})
+
+			t.Run("should report error if the slice doesn't fit on the array", func(t *testing.T) {
+				var output struct {
+					Array [2]int `map:"array"`
+				}
+				err := structi.ForEach(&output, func(field structi.Field) error {
+					return field.Set([]any{1, 2, 3})
+				})
+				tt.AssertErrContains(t, err, "Array", "3 items", "[2]int")
+			})
+		})
+
 		t.Run("should work with slices of nested structs/maps", func(t *testing.T) {
+			t.Run("input being a slice of maps and target a slice of structs", func(t *testing.T) {
+				var output struct {
+					Items []struct {
+						Name  string `map:"name"`
+						Price float64
+					} `map:"items"`
+				}
+				err := structi.ForEach(&output, func(field structi.Field) error {
+					return field.Set([]map[string]any{
+						{"name": "fakeName", "price": 10},
+					})
+				})
+				tt.AssertNoErr(t, err)
+				tt.AssertEqual(t, len(output.Items), 1)
+				tt.AssertEqual(t, output.Items[0].Name, "fakeName")
+				tt.AssertEqual(t, output.Items[0].Price, 10.0)
+			})
+
+			t.Run("should report the path of nested errors", func(t *testing.T) {
+				var output struct {
+					Items []struct {
+						Price float64
+					} `map:"items"`
+				}
+				err := structi.ForEach(&output, func(field structi.Field) error {
+					return field.Set([]any{
+						map[string]any{"price": 1},
+						map[string]any{"price": 2},
+						map[string]any{"price": 3},
+						map[string]any{"price": "notANumber"},
+					})
+				})
+				tt.AssertErrContains(t, err, "Items[3].Price", "string", "float64", "notANumber")
+			})
+
 			t.Run("input and target being a slice of maps", func(t *testing.T) {
 				var output struct {
 					Slice []map[string]any `map:"slice"`
@@ -269,6 +339,37 @@ func TestForEach(t *testing.T) {
 			tt.AssertEqual(t, output.Attr1, 10)
 		})
 
+		t.Run("should format numbers when setting string fields", func(t *testing.T) {
+			var output struct {
+				Attr1 string `env:"attr1"`
+			}
+			err := structi.ForEach(&output, func(field structi.Field) error {
+				return field.Set(65)
+			})
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, output.Attr1, "65")
+		})
+
+		t.Run("should convert times and durations using the layout tag", func(t *testing.T) {
+			var output struct {
+				CreatedAt time.Time  `env:"created_at"`
+				Birthday  *time.Time `env:"birthday" layout:"2006-01-02"`
+				Timeout   time.Duration
+			}
+			values := map[string]any{
+				"CreatedAt": "2024-03-15T10:30:00Z",
+				"Birthday":  "1990-01-02",
+				"Timeout":   "1m30s",
+			}
+			err := structi.ForEach(&output, func(field structi.Field) error {
+				return field.Set(values[field.Name])
+			})
+			tt.AssertNoErr(t, err)
+			tt.AssertEqual(t, output.CreatedAt, time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC))
+			tt.AssertEqual(t, *output.Birthday, time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC))
+			tt.AssertEqual(t, output.Timeout, 90*time.Second)
+		})
+
 		t.Run("should convert from ptr to non ptr", func(t *testing.T) {
 			var output struct {
 				Attr1 int `env:"attr1"`
@@ -388,6 +489,22 @@ func TestForEach(t *testing.T) {
 				}{},
 				expectErrToContain: []string{"Attr1", "int", "string", "this is not a number"},
 			},
+			{
+				desc:  "should report error if the number overflows the field type",
+				value: 300,
+				targetStruct: &struct {
+					Attr1 uint8 `some_tag:"attr1"`
+				}{},
+				expectErrToContain: []string{"Attr1", "300", "uint8", "overflowing"},
+			},
+			{
+				desc:  "should report error if the fractional part of the number would be lost",
+				value: 3.9,
+				targetStruct: &struct {
+					Attr1 int `some_tag:"attr1"`
+				}{},
+				expectErrToContain: []string{"Attr1", "3.9", "int", "fractional part"},
+			},
 			{
 				desc:  "should report error if tag has no name",
 				value: "example-value",
@@ -441,6 +558,50 @@ func TestForEach(t *testing.T) {
 		}
 	})
 
+	t.Run("should allow lossy numeric conversions on lenient fields", func(t *testing.T) {
+		var output struct {
+			Attr1 uint8 `convert:"lenient"`
+			Attr2 int   `convert:"lenient"`
+			Attr3 int
+		}
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			switch field.Name {
+			case "Attr1":
+				return field.Set(300)
+			case "Attr2":
+				return field.Set(3.9)
+			}
+			return nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, uint8(44))
+		tt.AssertEqual(t, output.Attr2, 3)
+
+		err = structi.ForEach(&output, func(field structi.Field) error {
+			return field.Set(3.9)
+		})
+		tt.AssertErrContains(t, err, "Attr3", "3.9")
+	})
+
+	t.Run("should allow lossy numeric conversions in lenient mode", func(t *testing.T) {
+		structi.SetLenientNumericConversion(true)
+		defer structi.SetLenientNumericConversion(false)
+
+		var output struct {
+			Attr1 uint8
+			Attr2 int
+		}
+		err := structi.ForEach(&output, func(field structi.Field) error {
+			if field.Name == "Attr1" {
+				return field.Set(300)
+			}
+			return field.Set(3.9)
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, output.Attr1, uint8(44))
+		tt.AssertEqual(t, output.Attr2, 3)
+	})
+
 	t.Run("wrap errors correctly", func(t *testing.T) {
 
 		t.Run("wrap error from Decoder", func(t *testing.T) {
@@ -0,0 +1,22 @@
+package tags
+
+import "strings"
+
+// SplitOptions splits tag values with the format used by the `json` tag,
+// e.g. `name,omitempty`, into the name and the list of options after it.
+func SplitOptions(tagValue string) (name string, options []string) {
+	parts := strings.Split(tagValue, ",")
+	return parts[0], parts[1:]
+}
+
+// HasOption checks if a tag value with the format used
+// by the `json` tag contains the given option.
+func HasOption(tagValue string, option string) bool {
+	_, options := SplitOptions(tagValue)
+	for _, opt := range options {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
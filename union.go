@@ -0,0 +1,84 @@
+package structi
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/vingarcia/structi/internal/types"
+)
+
+// RegisterUnionType registers the type of `value` as the implementation
+// of the interface I that should be used by Field.Set() when decoding a
+// map into a field of type I whose discriminator matches the one given, e.g.:
+//
+//	structi.RegisterUnionType[Shape]("circle", &Circle{})
+//
+//	type Drawing struct {
+//		// Set(map[string]any{"type": "circle", "radius": 2})
+//		// stores a &Circle{Radius: 2} on this field:
+//		Shape Shape `union:"type"`
+//	}
+//
+// The `union` tag contains the key of the map that holds the discriminator,
+// it can be omitted if the key was registered with RegisterUnion().
+func RegisterUnionType[I any](discriminator string, value I) {
+	ifaceType := reflect.TypeOf((*I)(nil)).Elem()
+	if ifaceType.Kind() != reflect.Interface {
+		panic(fmt.Errorf("structi: RegisterUnionType expects an interface type but got: %v", ifaceType))
+	}
+
+	concreteType := reflect.TypeOf(value)
+	if concreteType == nil {
+		panic(fmt.Errorf("structi: RegisterUnionType received a nil value for discriminator '%s'", discriminator))
+	}
+
+	types.RegisterUnionType(ifaceType, discriminator, concreteType)
+}
+
+// RegisterUnion registers all the implementations of the interface I
+// indexed by their discriminators, and the key of the map that holds
+// the discriminator, e.g.:
+//
+//	structi.RegisterUnion[Shape]("kind", map[string]Shape{
+//		"circle": &Circle{},
+//		"rect":   Rect{},
+//	})
+//
+// Unlike RegisterUnionType() this allows maps to be decoded into I
+// even without the `union` tag, e.g. when setting a []Shape field
+// with a []map[string]any{{"kind": "circle", "radius": 2}} or when
+// decoding maps into structs with fields of type I.
+//
+// Unknown discriminators cause errors listing the registered ones.
+func RegisterUnion[I any](discriminatorKey string, variants map[string]I) {
+	for discriminator, value := range variants {
+		RegisterUnionType[I](discriminator, value)
+	}
+
+	types.SetUnionDiscriminatorKey(reflect.TypeOf((*I)(nil)).Elem(), discriminatorKey)
+}
+
+// forEachInterface calls `forEach` with a pointer to the struct stored inside
+// the interface of `ifacePtr`, if it is not a pointer the struct is copied and
+// the updated copy is stored back on the interface after the iteration.
+func forEachInterface(ifacePtr reflect.Value, forEach func(structPtr reflect.Value) error) error {
+	ifaceValue := ifacePtr.Elem()
+	if ifaceValue.IsNil() {
+		return fmt.Errorf("cannot iterate over nil interface of type %v", ifaceValue.Type())
+	}
+
+	concreteValue := ifaceValue.Elem()
+	if concreteValue.Kind() == reflect.Ptr {
+		return forEach(concreteValue)
+	}
+
+	if concreteValue.Kind() != reflect.Struct {
+		return fmt.Errorf("expected interface to hold a struct or struct pointer but got: %v", concreteValue.Type())
+	}
+
+	tmp := reflect.New(concreteValue.Type())
+	tmp.Elem().Set(concreteValue)
+	err := forEach(tmp)
+	ifaceValue.Set(tmp.Elem())
+	return err
+}
@@ -0,0 +1,246 @@
+package structi_test
+
+import (
+	"testing"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64 `map:"radius"`
+}
+
+func (c *Circle) Area() float64 {
+	return 3 * c.Radius * c.Radius
+}
+
+type Rect struct {
+	Width  float64 `map:"width"`
+	Height float64 `map:"height"`
+}
+
+func (r Rect) Area() float64 {
+	return r.Width * r.Height
+}
+
+func init() {
+	structi.RegisterUnionType[Shape]("circle", &Circle{})
+	structi.RegisterUnionType[Shape]("rect", Rect{})
+}
+
+func TestUnionTypes(t *testing.T) {
+	t.Run("should decode maps into the registered implementation", func(t *testing.T) {
+		var drawing struct {
+			Main   Shape `union:"type"`
+			Second Shape `union:"type"`
+		}
+		inputs := map[string]any{
+			"Main":   map[string]any{"type": "circle", "radius": 2},
+			"Second": map[string]any{"type": "rect", "width": 2, "height": 3},
+		}
+		err := structi.ForEach(&drawing, func(field structi.Field) error {
+			return field.Set(inputs[field.Name])
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, drawing.Main, &Circle{Radius: 2})
+		tt.AssertEqual(t, drawing.Second, Rect{Width: 2, Height: 3})
+	})
+
+	t.Run("should still accept concrete values and nil", func(t *testing.T) {
+		var drawing struct {
+			Main   Shape `union:"type"`
+			Second Shape `union:"type"`
+		}
+		drawing.Second = Rect{}
+		err := structi.ForEach(&drawing, func(field structi.Field) error {
+			if field.Name == "Main" {
+				return field.Set(&Circle{Radius: 1})
+			}
+			return field.Set(nil)
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, drawing.Main, &Circle{Radius: 1})
+		tt.AssertEqual(t, drawing.Second, nil)
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		var drawing struct {
+			Main Shape `union:"type"`
+		}
+
+		err := structi.ForEach(&drawing, func(field structi.Field) error {
+			return field.Set(map[string]any{"type": "triangle"})
+		})
+		tt.AssertErrContains(t, err, "Main", "unknown type 'triangle'", "Shape", "expected one of: circle, rect")
+
+		err = structi.ForEach(&drawing, func(field structi.Field) error {
+			return field.Set(map[string]any{"radius": 2})
+		})
+		tt.AssertErrContains(t, err, "Main", "missing discriminator 'type'", "Shape", "circle, rect")
+
+		err = structi.ForEach(&drawing, func(field structi.Field) error {
+			return field.Set(map[string]any{"type": "circle", "radius": "notANumber"})
+		})
+		tt.AssertErrContains(t, err, "Main", "Radius", "notANumber")
+	})
+
+	t.Run("should report error if the discriminator is not a string", func(t *testing.T) {
+		var drawing struct {
+			Main Shape `union:"type"`
+		}
+
+		err := structi.ForEach(&drawing, func(field structi.Field) error {
+			return field.Set(map[string]any{"type": 42})
+		})
+		tt.AssertErrContains(t, err, "Main", "discriminator 'type'", "string", "int")
+	})
+
+	t.Run("should panic when registering non interface types", func(t *testing.T) {
+		defer func() {
+			tt.AssertErrContains(t, recover().(error), "expects an interface type", "Circle")
+		}()
+		structi.RegisterUnionType[*Circle]("circle", &Circle{})
+	})
+}
+
+func TestForEachOnInterfaces(t *testing.T) {
+	t.Run("should iterate over the struct pointer stored in an interface", func(t *testing.T) {
+		var shape Shape = &Circle{Radius: 1}
+
+		err := structi.ForEach(&shape, func(field structi.Field) error {
+			tt.AssertEqual(t, field.Name, "Radius")
+			return field.Set(5)
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, shape, &Circle{Radius: 5})
+	})
+
+	t.Run("should update structs stored by value in an interface", func(t *testing.T) {
+		var shape any = Rect{Width: 1, Height: 1}
+
+		err := structi.ForEach(&shape, func(field structi.Field) error {
+			return field.Set(2)
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, shape, Rect{Width: 2, Height: 2})
+	})
+
+	t.Run("should allow descending into interface fields", func(t *testing.T) {
+		drawing := struct {
+			Shape Shape
+		}{
+			Shape: &Circle{Radius: 1},
+		}
+
+		names := []string{}
+		err := structi.ForEach(&drawing, func(field structi.Field) error {
+			return structi.ForEach(field.Value, func(field structi.Field) error {
+				names = append(names, field.Name)
+				return nil
+			})
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, names, []string{"Radius"})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		var shape Shape
+		err := structi.ForEach(&shape, func(field structi.Field) error {
+			return nil
+		})
+		tt.AssertErrContains(t, err, "nil interface", "Shape")
+
+		var value any = 42
+		err = structi.ForEach(&value, func(field structi.Field) error {
+			return nil
+		})
+		tt.AssertErrContains(t, err, "expected interface to hold a struct", "int")
+	})
+}
+
+type Animal interface {
+	Sound() string
+}
+
+type Dog struct {
+	Name string `json:"name"`
+}
+
+func (d Dog) Sound() string {
+	return "woof"
+}
+
+type Cat struct {
+	Lives int `json:"lives"`
+}
+
+func (c *Cat) Sound() string {
+	return "meow"
+}
+
+func init() {
+	structi.RegisterUnion[Animal]("kind", map[string]Animal{
+		"dog": Dog{},
+		"cat": &Cat{},
+	})
+}
+
+func TestRegisteredUnions(t *testing.T) {
+	t.Run("should decode nested unions without tags", func(t *testing.T) {
+		var zoo struct {
+			Pets    []Animal
+			ByName  map[string]Animal
+			Keepers []struct {
+				Favorite Animal `json:"favorite"`
+			}
+		}
+		inputs := map[string]any{
+			"Pets": []any{
+				map[string]any{"kind": "dog", "name": "Rex"},
+				map[string]any{"kind": "cat", "lives": 7},
+			},
+			"ByName": map[string]any{
+				"Tom": map[string]any{"kind": "cat", "lives": 9},
+			},
+			"Keepers": []map[string]any{
+				{"favorite": map[string]any{"kind": "dog", "name": "Max"}},
+			},
+		}
+		err := structi.ForEach(&zoo, func(field structi.Field) error {
+			return field.Set(inputs[field.Name])
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, zoo.Pets, []Animal{Dog{Name: "Rex"}, &Cat{Lives: 7}})
+		tt.AssertEqual(t, zoo.ByName, map[string]Animal{"Tom": &Cat{Lives: 9}})
+		tt.AssertEqual(t, zoo.Keepers[0].Favorite, Dog{Name: "Max"})
+	})
+
+	t.Run("should report the path and the allowed values of unknown discriminators", func(t *testing.T) {
+		var zoo struct {
+			Pets []Animal
+		}
+		err := structi.ForEach(&zoo, func(field structi.Field) error {
+			return field.Set([]any{
+				map[string]any{"kind": "dog"},
+				map[string]any{"kind": "parrot"},
+			})
+		})
+		tt.AssertErrContains(t, err, "Pets[1]", "unknown kind 'parrot'", "Animal", "expected one of: cat, dog")
+	})
+
+	t.Run("should let the union tag override the registered key", func(t *testing.T) {
+		var zoo struct {
+			Pet Animal `union:"type"`
+		}
+		err := structi.ForEach(&zoo, func(field structi.Field) error {
+			return field.Set(map[string]any{"type": "dog", "name": "Rex"})
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, zoo.Pet, Dog{Name: "Rex"})
+	})
+}
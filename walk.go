@@ -0,0 +1,172 @@
+package structi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrCycle is returned by Walk() when it finds a cycle
+// and the ErrorOnCycles policy is used.
+var ErrCycle = errors.New("cycle detected")
+
+// CyclePolicy defines how Walk() handles pointers
+// to structs that are already being visited
+type CyclePolicy int
+
+const (
+	// SkipCycles makes Walk() ignore the pointers that would cause a cycle
+	SkipCycles CyclePolicy = iota
+
+	// ErrorOnCycles makes Walk() return an error wrapping ErrCycle
+	ErrorOnCycles
+)
+
+// WalkFunc is called by Walk() for each field found during the traversal,
+// the path contains the names of the fields and the indexes/keys of the
+// slices and maps that lead to the field, e.g. `Children[0].Parent`.
+type WalkFunc func(path string, field Field) error
+
+// WalkOptions allows the user to customize the behavior of Walk()
+type WalkOptions struct {
+	// MaxDepth limits the number of nested struct levels visited,
+	// e.g. 1 visits only the fields of the root struct, 2 also visits
+	// the fields of its nested structs and so on.
+	//
+	// Zero means no limit.
+	MaxDepth int
+
+	// OnCycle is SkipCycles by default
+	OnCycle CyclePolicy
+}
+
+// Walk recursively traverses the input struct calling the `fn` function
+// for each field, including the fields of the nested structs found on
+// struct fields, pointers, slices, arrays, interfaces and map values
+// (only pointer map values, since map values are not addressable).
+//
+// Pointers to structs that are already being visited, i.e. that would cause
+// an infinite loop, are handled according to the WalkOptions.OnCycle policy.
+func Walk(targetStruct any, fn WalkFunc, opts ...WalkOptions) error {
+	var o WalkOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	_, v, _, err := getStructInfo(targetStruct)
+	if err != nil {
+		return err
+	}
+
+	w := walker{
+		fn:       fn,
+		opts:     o,
+		visiting: map[visitKey]bool{},
+	}
+	return w.walkValue(v, "", 0)
+}
+
+type visitKey struct {
+	ptr uintptr
+	t   reflect.Type
+}
+
+type walker struct {
+	fn       WalkFunc
+	opts     WalkOptions
+	visiting map[visitKey]bool
+}
+
+func (w walker) walkValue(v reflect.Value, path string, depth int) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		if v.Elem().Kind() != reflect.Struct {
+			return w.walkValue(v.Elem(), path, depth)
+		}
+
+		key := visitKey{ptr: v.Pointer(), t: v.Type()}
+		if w.visiting[key] {
+			if w.opts.OnCycle == ErrorOnCycles {
+				return fmt.Errorf("%w on '%s': %v already being visited", ErrCycle, path, v.Type())
+			}
+			return nil
+		}
+
+		w.visiting[key] = true
+		defer delete(w.visiting, key)
+
+		return w.walkStruct(v, path, depth)
+
+	case reflect.Struct:
+		return w.walkStruct(v.Addr(), path, depth)
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := w.walkValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), depth)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		for _, key := range sortedMapKeys(v) {
+			value := v.MapIndex(key)
+			if value.Kind() == reflect.Interface {
+				value = value.Elem()
+			}
+
+			if value.Kind() != reflect.Ptr {
+				continue
+			}
+
+			err := w.walkValue(value, fmt.Sprintf("%s[%v]", path, key), depth)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Interface:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Ptr {
+			return w.walkValue(v.Elem(), path, depth)
+		}
+	}
+
+	return nil
+}
+
+func (w walker) walkStruct(structPtr reflect.Value, path string, depth int) error {
+	if w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth {
+		return nil
+	}
+
+	_, v, fields, err := getStructInfo(structPtr)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		field := field
+		fieldPath := joinPath(path, field.Name)
+		fieldValue := v.Elem().Field(field.idx)
+
+		err := w.fn(fieldPath, Field{
+			fieldInfo: &field,
+			Value:     fieldValue.Addr().Interface(),
+			Set:       setAttrValue(v, field),
+		})
+		if err != nil {
+			return fmt.Errorf("walk error on field '%s' of type '%v': %w", fieldPath, field.Type, err)
+		}
+
+		err = w.walkValue(fieldValue, fieldPath, depth+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
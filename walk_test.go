@@ -0,0 +1,162 @@
+package structi_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vingarcia/structi"
+	tt "github.com/vingarcia/structi/internal/testtools"
+)
+
+type walkNode struct {
+	Name     string
+	Children []*walkNode
+	Parent   *walkNode
+}
+
+func TestWalk(t *testing.T) {
+	t.Run("should visit nested fields with their paths", func(t *testing.T) {
+		type Address struct {
+			City string
+		}
+		input := struct {
+			Name      string
+			Address   Address
+			Work      *Address
+			Others    []Address
+			ByName    map[string]*Address
+			Any       any
+			unexposed int
+		}{
+			Work:      &Address{},
+			Others:    []Address{{}, {}},
+			ByName:    map[string]*Address{"b": {}, "a": {}},
+			Any:       &Address{},
+			unexposed: 1,
+		}
+
+		paths := []string{}
+		err := structi.Walk(&input, func(path string, field structi.Field) error {
+			paths = append(paths, path)
+			return nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, paths, []string{
+			"Name",
+			"Address",
+			"Address.City",
+			"Work",
+			"Work.City",
+			"Others",
+			"Others[0].City",
+			"Others[1].City",
+			"ByName",
+			"ByName[a].City",
+			"ByName[b].City",
+			"Any",
+			"Any.City",
+		})
+		tt.AssertEqual(t, input.unexposed, 1)
+	})
+
+	t.Run("should allow setting the visited fields", func(t *testing.T) {
+		root := &walkNode{Name: "root", Children: []*walkNode{{Name: "child"}}}
+
+		err := structi.Walk(root, func(path string, field structi.Field) error {
+			if field.Name != "Name" {
+				return nil
+			}
+			return field.Set(path)
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, root.Name, "Name")
+		tt.AssertEqual(t, root.Children[0].Name, "Children[0].Name")
+	})
+
+	t.Run("should skip cycles by default", func(t *testing.T) {
+		root := &walkNode{Name: "root"}
+		child := &walkNode{Name: "child", Parent: root}
+		root.Children = []*walkNode{child}
+
+		paths := []string{}
+		err := structi.Walk(root, func(path string, field structi.Field) error {
+			paths = append(paths, path)
+			return nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, paths, []string{
+			"Name",
+			"Children",
+			"Children[0].Name",
+			"Children[0].Children",
+			"Children[0].Parent",
+			"Parent",
+		})
+	})
+
+	t.Run("should visit shared pointers that don't form cycles", func(t *testing.T) {
+		shared := &walkNode{Name: "shared"}
+		root := &walkNode{Children: []*walkNode{shared, shared}}
+
+		names := []string{}
+		err := structi.Walk(root, func(path string, field structi.Field) error {
+			if path == "Children[0].Name" || path == "Children[1].Name" {
+				names = append(names, *field.Value.(*string))
+			}
+			return nil
+		})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, names, []string{"shared", "shared"})
+	})
+
+	t.Run("should report cycles as errors if requested", func(t *testing.T) {
+		root := &walkNode{Name: "root"}
+		root.Children = []*walkNode{{Parent: root}}
+
+		err := structi.Walk(root, func(path string, field structi.Field) error {
+			return nil
+		}, structi.WalkOptions{OnCycle: structi.ErrorOnCycles})
+		tt.AssertEqual(t, errors.Is(err, structi.ErrCycle), true)
+		tt.AssertErrContains(t, err, "Children[0].Parent", "walkNode")
+	})
+
+	t.Run("should respect the max depth", func(t *testing.T) {
+		root := &walkNode{
+			Children: []*walkNode{
+				{Children: []*walkNode{{}}},
+			},
+		}
+
+		paths := []string{}
+		err := structi.Walk(root, func(path string, field structi.Field) error {
+			paths = append(paths, path)
+			return nil
+		}, structi.WalkOptions{MaxDepth: 2})
+		tt.AssertNoErr(t, err)
+		tt.AssertEqual(t, paths, []string{
+			"Name",
+			"Children",
+			"Children[0].Name",
+			"Children[0].Children",
+			"Children[0].Parent",
+			"Parent",
+		})
+	})
+
+	t.Run("should report errors correctly", func(t *testing.T) {
+		root := &walkNode{Children: []*walkNode{{}}}
+		err := structi.Walk(root, func(path string, field structi.Field) error {
+			if path == "Children[0].Name" {
+				return fmt.Errorf("fakeErrMsg")
+			}
+			return nil
+		})
+		tt.AssertErrContains(t, err, "walk error", "Children[0].Name", "string", "fakeErrMsg")
+
+		err = structi.Walk(walkNode{}, func(path string, field structi.Field) error {
+			return nil
+		})
+		tt.AssertErrContains(t, err, "expected struct pointer", "walkNode")
+	})
+}